@@ -131,6 +131,9 @@ func (c *ContainerFile) validate() error {
 type ContainerRequest struct {
 	FromDockerfile
 	HostAccessPorts          []int
+	HostAccessMode           HostAccessMode    // how the container reaches the host ports, see HostAccessMode
+	HostAccessSockets        map[string]string // host Unix sockets forwarded to the container, by container path
+	StablePorts              bool              // pin the host ports, so that they're kept when the container is restarted
 	Image                    string
 	ImageSubstitutors        []ImageSubstitutor
 	Entrypoint               []string
@@ -168,6 +171,7 @@ type ContainerRequest struct {
 	EndpointSettingsModifier func(map[string]*network.EndpointSettings) // Modifier for the network settings before container creation
 	LifecycleHooks           []ContainerLifecycleHooks                  // define hooks to be executed during container lifecycle
 	LogConsumerCfg           *LogConsumerConfig                         // define the configuration for the log producer and its log consumers to follow the logs
+	HealthCheck              *container.HealthConfig                    // healthcheck of the container, overriding the one of the image
 }
 
 // sessionID returns the session ID for the container request.
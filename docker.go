@@ -3,6 +3,7 @@ package testcontainers
 import (
 	"archive/tar"
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
@@ -10,12 +11,14 @@ import (
 	"io"
 	"io/fs"
 	"net"
+	"net/netip"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -52,6 +55,9 @@ var (
 	// createContainerFailDueToNameConflictRegex is a regular expression that matches the container is already in use error.
 	createContainerFailDueToNameConflictRegex = regexp.MustCompile("[Tt]he container name .* is already in use by .*")
 
+	// startContainerFailDueToPortConflictRegex is a regular expression that matches the host port is already in use error.
+	startContainerFailDueToPortConflictRegex = regexp.MustCompile("port is already allocated|address already in use")
+
 	// minLogProductionTimeout is the minimum log production timeout.
 	minLogProductionTimeout = time.Duration(5 * time.Second)
 
@@ -79,11 +85,21 @@ type DockerContainer struct {
 	terminationSignal chan bool
 	consumersMtx      sync.Mutex // protects consumers
 	consumers         []LogConsumer
+	processesMtx      sync.Mutex // protects processes
+	processes         []*ExecProcess
+	watchdog          atomic.Pointer[crashWatchdog] // set by WithCrashWatchdog
+	artifacts         failureArtifacts              // set by WithFailureArtifacts
+	hostAccessMtx     sync.Mutex                    // protects hostAccess and hostAccessHooked
+	hostAccess        *sshdContainer                // set by WithHostPortAccess or ExposeHostPort
+	hostAccessHooked  bool                          // whether the hooks of the tunnel started by ExposeHostPort were added
+	reservedPorts     *portReservation              // set by WithStablePorts, released when the container starts
 
 	// TODO: Remove locking and wait group once the deprecated StartLogProducer and
 	// StopLogProducer have been removed and hence logging can only be started and
 	// stopped once.
 
+	// logProductionMtx protects the log production fields below.
+	logProductionMtx sync.Mutex
 	// logProductionCancel is used to signal the log production to stop.
 	logProductionCancel context.CancelCauseFunc
 	logProductionCtx    context.Context
@@ -91,8 +107,11 @@ type DockerContainer struct {
 	logProductionDone chan struct{}
 
 	logProductionTimeout *time.Duration
-	logger               log.Logger
-	lifecycleHooks       []ContainerLifecycleHooks
+	// logWatchers receives the logs of the log production, for the log expectations.
+	logWatchers       logWatchers
+	logger            log.Logger
+	lifecycleHooksMtx sync.Mutex // protects lifecycleHooks
+	lifecycleHooks    []ContainerLifecycleHooks
 
 	healthStatus container.HealthStatus // container health status, will default to healthStatusNone if no healthcheck is present
 }
@@ -121,7 +140,7 @@ func (c *DockerContainer) IsRunning() bool {
 }
 
 // Endpoint gets proto://host:port string for the lowest numbered exposed port
-// Will returns just host:port if proto is ""
+// Will returns just host:port if proto is "", the host being bracketed if it's an IPv6 address
 func (c *DockerContainer) Endpoint(ctx context.Context, proto string) (string, error) {
 	inspect, err := c.Inspect(ctx)
 	if err != nil {
@@ -218,7 +237,17 @@ func (c *DockerContainer) MappedPort(ctx context.Context, port string) (network.
 		if len(p) == 0 {
 			continue
 		}
-		pNum, _ := strconv.ParseUint(p[0].HostPort, 10, 16)
+
+		binding := p[0]
+		if len(p) > 1 {
+			host, err := c.Host(ctx)
+			if err != nil {
+				return network.Port{}, fmt.Errorf("host: %w", err)
+			}
+			binding = hostPortBinding(p, host)
+		}
+
+		pNum, _ := strconv.ParseUint(binding.HostPort, 10, 16)
 		hPort, _ := network.PortFrom(uint16(pNum), k.Proto())
 		return hPort, nil
 	}
@@ -226,6 +255,25 @@ func (c *DockerContainer) MappedPort(ctx context.Context, port string) (network.
 	return network.Port{}, errdefs.ErrNotFound.WithMessage(fmt.Sprintf("port %q not found", nwPort))
 }
 
+// hostPortBinding returns the binding of a port reachable from the host, as the
+// IPv4 and IPv6 bindings of a port may get different host ports, see
+// https://github.com/moby/moby/issues/42442. An IPv6 host uses the IPv6 bindings,
+// any other host, including a host name, the IPv4 ones, falling back to the first binding.
+func hostPortBinding(bindings []network.PortBinding, host string) network.PortBinding {
+	var ipv6 bool
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		ipv6 = addr.Unmap().Is6()
+	}
+
+	for _, b := range bindings {
+		if !b.HostIP.IsValid() || b.HostIP.Unmap().Is6() == ipv6 {
+			return b
+		}
+	}
+
+	return bindings[0]
+}
+
 // Deprecated: use c.Inspect(ctx).NetworkSettings.Ports instead.
 // Ports gets the exposed ports for the container.
 func (c *DockerContainer) Ports(ctx context.Context) (network.PortMap, error) {
@@ -248,6 +296,9 @@ func (c *DockerContainer) Start(ctx context.Context) error {
 		return fmt.Errorf("starting hook: %w", err)
 	}
 
+	// Docker binds the host ports pinned by WithStablePorts.
+	c.reservedPorts.release()
+
 	if _, err := c.provider.client.ContainerStart(ctx, c.ID, client.ContainerStartOptions{}); err != nil {
 		return fmt.Errorf("container start: %w", err)
 	}
@@ -268,6 +319,22 @@ func (c *DockerContainer) Start(ctx context.Context) error {
 	return nil
 }
 
+// Restart stops the container and starts it again, running the lifecycle hooks of
+// both, including the wait strategy. The host ports mapped to the exposed ports are
+// kept if the container was created with [WithStablePorts], otherwise Docker assigns
+// new random ones, so [DockerContainer.MappedPort] must be called again.
+func (c *DockerContainer) Restart(ctx context.Context) error {
+	if err := c.Stop(ctx, nil); err != nil {
+		return fmt.Errorf("restart: %w", err)
+	}
+
+	if err := c.Start(ctx); err != nil {
+		return fmt.Errorf("restart: %w", err)
+	}
+
+	return nil
+}
+
 // Stop stops the container.
 //
 // In case the container fails to stop gracefully within a time frame specified
@@ -335,6 +402,10 @@ func (c *DockerContainer) Terminate(ctx context.Context, opts ...TerminateOption
 		return fmt.Errorf("stop: %w", err)
 	}
 
+	// Background processes are gone with the container.
+	c.closeProcesses()
+	c.reservedPorts.release()
+
 	select {
 	// Close reaper connection if it was attached.
 	case c.terminationSignal <- true:
@@ -386,10 +457,49 @@ func (c *DockerContainer) inspectRawContainer(ctx context.Context) (*client.Cont
 // Logs will fetch both STDOUT and STDERR from the current container. Returns a
 // ReadCloser and leaves it up to the caller to extract what it wants.
 func (c *DockerContainer) Logs(ctx context.Context) (io.ReadCloser, error) {
-	rc, err := c.provider.client.ContainerLogs(ctx, c.ID, client.ContainerLogsOptions{
+	return c.LogsWithOptions(ctx, LogsOptions{})
+}
+
+// LogsOptions are the options to fetch the logs of a container with
+// [DockerContainer.LogsWithOptions].
+type LogsOptions struct {
+	// Since only returns the logs produced after it, if not zero.
+	Since time.Time
+
+	// Until only returns the logs produced before it, if not zero.
+	Until time.Time
+
+	// Tail only returns the given number of lines from the end of the logs, if positive.
+	Tail int
+
+	// Follow keeps streaming the logs until the container stops or the context is done.
+	Follow bool
+
+	// Timestamps prefixes each line with the RFC 3339 timestamp of the time it
+	// was received by Docker, followed by a space.
+	Timestamps bool
+}
+
+// LogsWithOptions fetches both STDOUT and STDERR from the current container,
+// as [DockerContainer.Logs], filtered according to the given options.
+func (c *DockerContainer) LogsWithOptions(ctx context.Context, opts LogsOptions) (io.ReadCloser, error) {
+	options := client.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
-	})
+		Follow:     opts.Follow,
+		Timestamps: opts.Timestamps,
+	}
+	if !opts.Since.IsZero() {
+		options.Since = fmt.Sprintf("%d.%09d", opts.Since.Unix(), int64(opts.Since.Nanosecond()))
+	}
+	if !opts.Until.IsZero() {
+		options.Until = fmt.Sprintf("%d.%09d", opts.Until.Unix(), int64(opts.Until.Nanosecond()))
+	}
+	if opts.Tail > 0 {
+		options.Tail = strconv.Itoa(opts.Tail)
+	}
+
+	rc, err := c.provider.client.ContainerLogs(ctx, c.ID, options)
 	if err != nil {
 		return nil, err
 	}
@@ -397,6 +507,7 @@ func (c *DockerContainer) Logs(ctx context.Context) (io.ReadCloser, error) {
 
 	resp, err := c.Inspect(ctx)
 	if err != nil {
+		rc.Close()
 		return nil, err
 	}
 
@@ -502,7 +613,8 @@ func (c *DockerContainer) Networks(ctx context.Context) ([]string, error) {
 	return n, nil
 }
 
-// ContainerIP gets the IP address of the primary network within the container.
+// ContainerIP gets the IP address of the primary network within the container,
+// its IPv4 address, or its IPv6 address if the network is IPv6-only.
 func (c *DockerContainer) ContainerIP(ctx context.Context) (string, error) {
 	inspect, err := c.Inspect(ctx)
 	if err != nil {
@@ -514,8 +626,11 @@ func (c *DockerContainer) ContainerIP(ctx context.Context) (string, error) {
 	networks := inspect.NetworkSettings.Networks
 	if len(networks) == 1 {
 		for _, v := range networks {
-			if v.IPAddress.IsValid() {
+			switch {
+			case v.IPAddress.IsValid():
 				ip = v.IPAddress.String()
+			case v.GlobalIPv6Address.IsValid():
+				ip = v.GlobalIPv6Address.String()
 			}
 		}
 	}
@@ -523,7 +638,8 @@ func (c *DockerContainer) ContainerIP(ctx context.Context) (string, error) {
 	return ip, nil
 }
 
-// ContainerIPs gets the IP addresses of all the networks within the container.
+// ContainerIPs gets the IP addresses of all the networks within the container,
+// both the IPv4 and the IPv6 ones.
 func (c *DockerContainer) ContainerIPs(ctx context.Context) ([]string, error) {
 	inspect, err := c.Inspect(ctx)
 	if err != nil {
@@ -536,6 +652,9 @@ func (c *DockerContainer) ContainerIPs(ctx context.Context) ([]string, error) {
 		if nw.IPAddress.IsValid() {
 			ips = append(ips, nw.IPAddress.String())
 		}
+		if nw.GlobalIPv6Address.IsValid() {
+			ips = append(ips, nw.GlobalIPv6Address.String())
+		}
 	}
 
 	return ips, nil
@@ -582,7 +701,10 @@ func (c *DockerContainer) Exec(ctx context.Context, cmd []string, options ...tce
 		return 0, nil, fmt.Errorf("container exec create: %w", err)
 	}
 
-	hijack, err := cli.ExecAttach(ctx, response.ID, client.ExecAttachOptions{})
+	hijack, err := cli.ExecAttach(ctx, response.ID, client.ExecAttachOptions{
+		TTY:         processOptions.ExecConfig.TTY,
+		ConsoleSize: processOptions.ExecConfig.ConsoleSize,
+	})
 	if err != nil {
 		return 0, nil, fmt.Errorf("container exec attach: %w", err)
 	}
@@ -595,19 +717,9 @@ func (c *DockerContainer) Exec(ctx context.Context, cmd []string, options ...tce
 		o.Apply(processOptions)
 	}
 
-	var exitCode int
-	for {
-		execResp, err := cli.ExecInspect(ctx, response.ID, client.ExecInspectOptions{})
-		if err != nil {
-			return 0, nil, fmt.Errorf("container exec inspect: %w", err)
-		}
-
-		if !execResp.Running {
-			exitCode = execResp.ExitCode
-			break
-		}
-
-		time.Sleep(100 * time.Millisecond)
+	exitCode, err := c.execExitCode(ctx, response.ID)
+	if err != nil {
+		return 0, nil, err
 	}
 
 	return exitCode, processOptions.Reader, nil
@@ -746,29 +858,133 @@ func (c *DockerContainer) copyToContainer(ctx context.Context, fileContent func(
 	return nil
 }
 
+// maxPartialLogLine is the size above which a line without newline is sent
+// to the line consumers without waiting for the rest of it.
+const maxPartialLogLine = 1 << 20
+
 // logConsumerWriter is a writer that writes to a LogConsumer.
 type logConsumerWriter struct {
-	log       Log
-	consumers []LogConsumer
+	log Log
+
+	// consumers receive the chunks of the logs as they are written,
+	// and lineConsumers complete lines, see [LineLogConsumer].
+	consumers     []LogConsumer
+	lineConsumers []LogConsumer
+
+	// timestamps is true when each line is prefixed by its Docker timestamp.
+	timestamps bool
+
+	// partial is the beginning of a line which was not terminated yet,
+	// and partialTimestamp the timestamp of its first chunk.
+	partial          []byte
+	partialTimestamp time.Time
 }
 
 // newLogConsumerWriter creates a new logConsumerWriter for logType that sends messages to all consumers.
 func newLogConsumerWriter(logType string, consumers []LogConsumer) *logConsumerWriter {
-	return &logConsumerWriter{
-		log:       Log{LogType: logType},
-		consumers: consumers,
+	lw := &logConsumerWriter{
+		log: Log{LogType: logType},
 	}
+
+	for _, consumer := range consumers {
+		if lc, ok := consumer.(LineLogConsumer); ok && lc.AcceptLines() {
+			lw.lineConsumers = append(lw.lineConsumers, consumer)
+		} else {
+			lw.consumers = append(lw.consumers, consumer)
+		}
+	}
+
+	return lw
+}
+
+// newTimestampedLogConsumerWriter creates a new logConsumerWriter for logType that sends
+// messages to all consumers, parsing the Docker timestamp that prefixes each line.
+func newTimestampedLogConsumerWriter(logType string, consumers []LogConsumer) *logConsumerWriter {
+	lw := newLogConsumerWriter(logType, consumers)
+	lw.timestamps = true
+	return lw
 }
 
 // Write writes the p content to all consumers.
-func (lw logConsumerWriter) Write(p []byte) (int, error) {
-	lw.log.Content = p
-	for _, consumer := range lw.consumers {
-		consumer.Accept(lw.log)
+// When timestamps are enabled, each chunk is sent with its timestamp. The line
+// consumers receive each line as a separate message, with the timestamp of its
+// first chunk: the chunks of a line, e.g. split by Docker across several frames,
+// are buffered until the line is complete.
+func (lw *logConsumerWriter) Write(p []byte) (int, error) {
+	if !lw.timestamps {
+		lw.send(lw.consumers, p, time.Time{})
+	}
+
+	if !lw.timestamps && len(lw.lineConsumers) == 0 {
+		return len(p), nil
 	}
+
+	for chunk := range bytes.Lines(p) {
+		var ts time.Time
+		content := chunk
+		if lw.timestamps {
+			ts, content = parseLogTimestamp(chunk)
+			lw.send(lw.consumers, content, ts)
+		}
+
+		if len(lw.lineConsumers) == 0 {
+			continue
+		}
+
+		if lw.partial == nil {
+			if bytes.HasSuffix(content, []byte{'\n'}) {
+				lw.send(lw.lineConsumers, content, ts)
+				continue
+			}
+			lw.partialTimestamp = ts
+		}
+
+		lw.partial = append(lw.partial, content...)
+		if bytes.HasSuffix(content, []byte{'\n'}) || len(lw.partial) >= maxPartialLogLine {
+			lw.Flush()
+		}
+	}
+
 	return len(p), nil
 }
 
+// Flush sends the buffered beginning of a line, if any, to the line consumers.
+// It's meant to be called once the logs stream ends.
+func (lw *logConsumerWriter) Flush() {
+	if lw.partial == nil {
+		return
+	}
+
+	content := lw.partial
+	lw.partial = nil
+	lw.send(lw.lineConsumers, content, lw.partialTimestamp)
+}
+
+// send sends content to consumers.
+func (lw *logConsumerWriter) send(consumers []LogConsumer, content []byte, ts time.Time) {
+	lw.log.Content = content
+	lw.log.Timestamp = ts
+	for _, consumer := range consumers {
+		consumer.Accept(lw.log)
+	}
+}
+
+// parseLogTimestamp splits the Docker timestamp prefixing line from its content.
+// It returns a zero time and the unmodified line if it's not prefixed by a timestamp.
+func parseLogTimestamp(line []byte) (time.Time, []byte) {
+	before, after, ok := bytes.Cut(line, []byte{' '})
+	if !ok {
+		return time.Time{}, line
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, string(before))
+	if err != nil {
+		return time.Time{}, line
+	}
+
+	return ts, after
+}
+
 type LogProductionOption func(*DockerContainer)
 
 // WithLogProductionTimeout is a functional option that sets the timeout for the log production.
@@ -793,6 +1009,36 @@ func (c *DockerContainer) StartLogProducer(ctx context.Context, opts ...LogProdu
 // Use functional option WithLogProductionTimeout() to override default timeout. If it's
 // lower than 5s and greater than 60s it will be set to 5s or 60s respectively.
 func (c *DockerContainer) startLogProduction(ctx context.Context, opts ...LogProductionOption) error {
+	c.logProductionMtx.Lock()
+	defer c.logProductionMtx.Unlock()
+
+	return c.startLogProductionLocked(ctx, false, opts...)
+}
+
+// ensureLogProduction starts the log production if it's not running, so that
+// the log watchers receive the logs, skipping the logs already emitted.
+func (c *DockerContainer) ensureLogProduction() (<-chan struct{}, error) {
+	c.logProductionMtx.Lock()
+	defer c.logProductionMtx.Unlock()
+
+	if c.logProductionDone != nil {
+		select {
+		case <-c.logProductionDone:
+		default:
+			return c.logProductionDone, nil
+		}
+	}
+
+	if err := c.startLogProductionLocked(context.Background(), true); err != nil {
+		return nil, err
+	}
+
+	return c.logProductionDone, nil
+}
+
+// startLogProductionLocked starts the log production, from the beginning of the
+// logs unless newLogsOnly is true. The caller must hold logProductionMtx.
+func (c *DockerContainer) startLogProductionLocked(ctx context.Context, newLogsOnly bool, opts ...LogProductionOption) error {
 	for _, opt := range opts {
 		opt(c)
 	}
@@ -809,9 +1055,9 @@ func (c *DockerContainer) startLogProduction(ctx context.Context, opts ...LogPro
 
 	// Setup the log writers.
 
-	consumers := c.consumersCopy()
-	stdout := newLogConsumerWriter(StdoutLog, consumers)
-	stderr := newLogConsumerWriter(StderrLog, consumers)
+	consumers := append(c.consumersCopy(), &c.logWatchers)
+	stdout := newTimestampedLogConsumerWriter(StdoutLog, consumers)
+	stderr := newTimestampedLogConsumerWriter(StderrLog, consumers)
 
 	// Setup the log production context which will be used to stop the log production.
 	c.logProductionCtx, c.logProductionCancel = context.WithCancelCause(ctx)
@@ -826,7 +1072,11 @@ func (c *DockerContainer) startLogProduction(ctx context.Context, opts ...LogPro
 		// Signal that the goroutine has exited so stopLogProduction can drain.
 		defer close(done)
 
-		c.logProducer(stdout, stderr)
+		c.logProducer(stdout, stderr, newLogsOnly)
+
+		// Send the last lines if they were not terminated.
+		stdout.Flush()
+		stderr.Flush()
 	}(c.logProductionCancel, c.logProductionDone)
 
 	return nil
@@ -836,7 +1086,9 @@ func (c *DockerContainer) startLogProduction(ctx context.Context, opts ...LogPro
 //   - logProductionCtx is done
 //   - A fatal error occurs
 //   - No more logs are available
-func (c *DockerContainer) logProducer(stdout, stderr io.Writer) {
+//
+// The logs already emitted are skipped if newLogsOnly is true.
+func (c *DockerContainer) logProducer(stdout, stderr io.Writer, newLogsOnly bool) {
 	// Clean up idle client connections.
 	defer c.provider.Close()
 
@@ -845,21 +1097,78 @@ func (c *DockerContainer) logProducer(stdout, stderr io.Writer) {
 		ShowStdout: true,
 		ShowStderr: true,
 		Follow:     true,
+		Timestamps: true,
+	}
+	if newLogsOnly {
+		// A tail of zero skips the existing logs.
+		options.Tail = "0"
+	}
+
+	// The logs of a container with a TTY are not multiplexed.
+	var tty bool
+	if resp, err := c.Inspect(c.logProductionCtx); err == nil {
+		tty = resp.Config.Tty
+	} else {
+		c.logger.Printf("Unexpected error inspecting container for logs: %v", err)
 	}
 
+	// Resume from the last log received when retrying.
+	tracker := &logTimestampTracker{}
+	stdout = io.MultiWriter(tracker, stdout)
+	stderr = io.MultiWriter(tracker, stderr)
+
 	// Use a separate method so that timeout cancel function is
 	// called correctly.
-	for c.copyLogsTimeout(stdout, stderr, options) {
+	for c.copyLogsTimeout(stdout, stderr, options, tty, tracker) {
 	}
 }
 
+// logTimestampTracker is a writer that records the timestamp of the last log line written.
+type logTimestampTracker struct {
+	mtx  sync.Mutex // protects last
+	last time.Time
+}
+
+// Write records the timestamp of the last line of p.
+func (t *logTimestampTracker) Write(p []byte) (int, error) {
+	var last time.Time
+	for line := range bytes.Lines(p) {
+		if ts, _ := parseLogTimestamp(line); !ts.IsZero() {
+			last = ts
+		}
+	}
+
+	if !last.IsZero() {
+		t.mtx.Lock()
+		t.last = last
+		t.mtx.Unlock()
+	}
+
+	return len(p), nil
+}
+
+// since returns the value of the since option to get the logs after the last one recorded,
+// or after now if no log was recorded.
+func (t *logTimestampTracker) since() string {
+	t.mtx.Lock()
+	last := t.last
+	t.mtx.Unlock()
+
+	next := time.Now()
+	if !last.IsZero() {
+		next = last.Add(time.Nanosecond)
+	}
+
+	return fmt.Sprintf("%d.%09d", next.Unix(), int64(next.Nanosecond()))
+}
+
 // copyLogsTimeout copies logs from the container to stdout and stderr with a timeout.
 // It returns true if the log production should be retried, false otherwise.
-func (c *DockerContainer) copyLogsTimeout(stdout, stderr io.Writer, options *client.ContainerLogsOptions) bool {
+func (c *DockerContainer) copyLogsTimeout(stdout, stderr io.Writer, options *client.ContainerLogsOptions, tty bool, tracker *logTimestampTracker) bool {
 	timeoutCtx, cancel := context.WithTimeout(c.logProductionCtx, *c.logProductionTimeout)
 	defer cancel()
 
-	err := c.copyLogs(timeoutCtx, stdout, stderr, *options)
+	err := c.copyLogs(timeoutCtx, stdout, stderr, *options, tty)
 	switch {
 	case err == nil:
 		// No more logs available.
@@ -874,22 +1183,47 @@ func (c *DockerContainer) copyLogsTimeout(stdout, stderr io.Writer, options *cli
 		c.logger.Printf("Unexpected error reading logs: %v", err)
 	}
 
-	// Retry from the last log received.
-	now := time.Now()
-	options.Since = fmt.Sprintf("%d.%09d", now.Unix(), int64(now.Nanosecond()))
+	// Retry from the last log received, including the logs emitted meanwhile.
+	options.Since = tracker.since()
+	options.Tail = ""
 
 	return true
 }
 
 // copyLogs copies logs from the container to stdout and stderr.
-func (c *DockerContainer) copyLogs(ctx context.Context, stdout, stderr io.Writer, options client.ContainerLogsOptions) error {
+func (c *DockerContainer) copyLogs(ctx context.Context, stdout, stderr io.Writer, options client.ContainerLogsOptions, tty bool) error {
 	rc, err := c.provider.client.ContainerLogs(ctx, c.GetContainerID(), options)
 	if err != nil {
 		return fmt.Errorf("container logs: %w", err)
 	}
 	defer rc.Close()
 
-	if _, err = stdcopy.StdCopy(stdout, stderr, rc); err != nil {
+	return demuxLogs(rc, stdout, stderr, tty)
+}
+
+// demuxLogs copies the logs stream r to stdout and stderr.
+// The logs of a container with a TTY are copied line by line to stdout,
+// as the TTY combines both streams.
+func demuxLogs(r io.Reader, stdout, stderr io.Writer, tty bool) error {
+	if tty {
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadBytes('\n')
+			if len(line) > 0 {
+				if _, errW := stdout.Write(line); errW != nil {
+					return fmt.Errorf("write: %w", errW)
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return fmt.Errorf("read: %w", err)
+			}
+		}
+	}
+
+	if _, err := stdcopy.StdCopy(stdout, stderr, r); err != nil {
 		return fmt.Errorf("stdcopy: %w", err)
 	}
 
@@ -904,6 +1238,9 @@ func (c *DockerContainer) StopLogProducer() error {
 // stopLogProduction will stop the concurrent process that is reading logs
 // and sending them to each added LogConsumer
 func (c *DockerContainer) stopLogProduction() error {
+	c.logProductionMtx.Lock()
+	defer c.logProductionMtx.Unlock()
+
 	if c.logProductionCancel == nil {
 		return nil
 	}
@@ -1005,8 +1342,10 @@ type DockerNetwork struct {
 	ID                string // Network ID from Docker
 	Driver            string
 	Name              string
-	provider          *DockerProvider
 	terminationSignal chan bool
+
+	providerMtx sync.Mutex // protects provider, created on first use if not set
+	provider    *DockerProvider
 }
 
 // Remove is used to remove the network. It is usually triggered by as defer function.
@@ -1017,9 +1356,13 @@ func (n *DockerNetwork) Remove(ctx context.Context) error {
 	default:
 	}
 
-	defer n.provider.Close()
+	provider, err := n.dockerProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
 
-	_, err := n.provider.client.NetworkRemove(ctx, n.ID, client.NetworkRemoveOptions{})
+	_, err = provider.client.NetworkRemove(ctx, n.ID, client.NetworkRemoveOptions{})
 	return err
 }
 
@@ -1225,11 +1568,12 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 	}
 
 	dockerInput := &container.Config{
-		Entrypoint: req.Entrypoint,
-		Image:      imageName,
-		Env:        env,
-		Labels:     req.Labels,
-		Cmd:        req.Cmd,
+		Entrypoint:  req.Entrypoint,
+		Image:       imageName,
+		Env:         env,
+		Labels:      req.Labels,
+		Cmd:         req.Cmd,
+		Healthcheck: req.HealthCheck,
 	}
 
 	hostConfig := &container.HostConfig{
@@ -1246,13 +1590,30 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 		defaultReadinessHook(),
 	)
 
-	// in the case the container needs to access a local port
-	// we need to forward the local port to the container
-	if len(req.HostAccessPorts) > 0 {
+	// in the host gateway mode, the container reaches the host directly
+	// when the daemon supports it, with no need to forward the ports.
+	hostGateway := false
+	if req.HostAccessMode == HostAccessGateway {
+		if errGateway := p.hostGatewaySupported(ctx); errGateway != nil {
+			p.Logger.Printf("🔌 Host gateway not available, falling back to the SSH tunnel: %s", errGateway)
+		} else {
+			withHostGateway(&req)
+			hostGateway = true
+		}
+	}
+
+	// in the case the container needs to access a local port or socket
+	// we need to forward it to the container
+	hostAccessPorts := req.HostAccessPorts
+	if hostGateway {
+		hostAccessPorts = nil
+	}
+
+	if len(hostAccessPorts) > 0 || len(req.HostAccessSockets) > 0 {
 		// a container lifecycle hook will be added, which will expose the host ports to the container
 		// using a SSHD server running in a container. The SSHD server will be started and will
 		// forward the host ports to the container ports.
-		sshdForwardPortsHook, err := exposeHostPorts(ctx, &req, req.HostAccessPorts...)
+		sshdForwardPortsHook, err := exposeHostPorts(ctx, &req, hostGateway, hostAccessPorts...)
 		if err != nil {
 			return nil, fmt.Errorf("expose host ports: %w", err)
 		}
@@ -1265,7 +1626,7 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 					logger:         p.Logger,
 					lifecycleHooks: []ContainerLifecycleHooks{sshdForwardPortsHook},
 				}
-				err = errors.Join(ctr.terminatingHook(ctx))
+				err = errors.Join(ctr.terminatingHook(ctx), ctr.terminatedHook(ctx))
 			}
 		}()
 
@@ -1282,6 +1643,19 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 		return nil, err
 	}
 
+	var reservedPorts *portReservation
+	if req.StablePorts {
+		if reservedPorts, err = p.reservePorts(hostConfig.PortBindings); err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			if err != nil && con == nil {
+				reservedPorts.release()
+			}
+		}()
+	}
+
 	resp, err := p.client.ContainerCreate(ctx, client.ContainerCreateOptions{
 		Config:           dockerInput,
 		HostConfig:       hostConfig,
@@ -1326,6 +1700,7 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 		provider:       p,
 		logger:         p.Logger,
 		lifecycleHooks: req.LifecycleHooks,
+		reservedPorts:  reservedPorts,
 	}
 
 	if err = ctr.connectReaper(ctx); err != nil {
@@ -1589,7 +1964,8 @@ func (p *DockerProvider) daemonHostLocked(ctx context.Context) (string, error) {
 
 	host, exists := os.LookupEnv("TESTCONTAINERS_HOST_OVERRIDE")
 	if exists {
-		p.hostCache = host
+		// An IPv6 address may be bracketed, as in a URL.
+		p.hostCache = strings.Trim(host, "[]")
 		return p.hostCache, nil
 	}
 
@@ -1645,6 +2021,7 @@ func (p *DockerProvider) CreateNetwork(ctx context.Context, req NetworkRequest)
 		Driver:     req.Driver,
 		Internal:   req.Internal,
 		EnableIPv6: req.EnableIPv6,
+		EnableIPv4: req.EnableIPv4,
 		Attachable: req.Attachable,
 		Labels:     req.Labels,
 		IPAM:       req.IPAM,
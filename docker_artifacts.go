@@ -0,0 +1,254 @@
+package testcontainers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+)
+
+// artifactsTimeout is the maximum time spent collecting the artifacts of a container.
+const artifactsTimeout = time.Minute
+
+// failureArtifacts is the configuration of the artifacts collected by
+// [CleanupContainer] when a test fails.
+type failureArtifacts struct {
+	dir   string
+	paths []string
+}
+
+// WithFailureArtifacts configures the artifacts collected by [CleanupContainer]
+// when the test failed: the logs, the inspect JSON and the recent events of the
+// container, plus the given paths of the container filesystem, files or directories.
+// They are written to a per-test directory under dir.
+//
+// If dir is empty, the directory configured with the TESTCONTAINERS_ARTIFACTS_DIR
+// environment variable, or the artifacts.dir property, is used. Modules can call
+// it with an empty dir to declare their relevant paths, such as log files, which
+// are collected only if the artifacts directory is configured.
+func WithFailureArtifacts(dir string, paths ...string) CustomizeRequestOption {
+	return WithAdditionalLifecycleHooks(ContainerLifecycleHooks{
+		PostCreates: []ContainerHook{
+			func(_ context.Context, c Container) error {
+				dockerContainer := c.(*DockerContainer)
+				if dir != "" {
+					dockerContainer.artifacts.dir = dir
+				}
+				dockerContainer.artifacts.paths = append(dockerContainer.artifacts.paths, paths...)
+				return nil
+			},
+		},
+	})
+}
+
+// CollectArtifacts writes the artifacts of the container to dir, which is created
+// if it does not exist, collecting as many of them as possible:
+//   - stdout.log and stderr.log: the logs of the container.
+//   - inspect.json: the inspect of the container.
+//   - events.json: the Docker events of the container since it was created.
+//   - files: the given paths of the container filesystem, keeping their absolute path.
+//     The paths that don't exist are skipped, as modules declare optional ones.
+func (c *DockerContainer) CollectArtifacts(ctx context.Context, dir string, paths ...string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	var errs []error
+
+	if err := c.writeLogsArtifacts(ctx, dir); err != nil {
+		errs = append(errs, fmt.Errorf("logs: %w", err))
+	}
+
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("inspect: %w", err))
+	} else if err := writeJSONArtifact(filepath.Join(dir, "inspect.json"), inspect); err != nil {
+		errs = append(errs, fmt.Errorf("inspect: %w", err))
+	}
+
+	if inspect != nil {
+		recent, err := c.recentEvents(ctx, inspect.Created)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("events: %w", err))
+		}
+		if err := writeJSONArtifact(filepath.Join(dir, "events.json"), recent); err != nil {
+			errs = append(errs, fmt.Errorf("events: %w", err))
+		}
+	}
+
+	for _, p := range paths {
+		err := c.copyArtifact(ctx, p, filepath.Join(dir, "files", filepath.FromSlash(p)))
+		if err != nil && !errdefs.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("copy %s: %w", p, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// collectFailureArtifacts collects the artifacts of the container into a
+// directory named after the test, if an artifacts directory is configured.
+func (c *DockerContainer) collectFailureArtifacts(tb testing.TB) {
+	dir := c.artifacts.dir
+	if dir == "" {
+		dir = c.provider.config.ArtifactsDir
+	}
+	if dir == "" {
+		return
+	}
+
+	name := c.ID[:12]
+	if c.Image != "" {
+		name = sanitizeArtifactName(c.Image) + "-" + name
+	}
+	dir = filepath.Join(dir, sanitizeArtifactName(tb.Name()), name)
+
+	ctx, cancel := context.WithTimeout(context.Background(), artifactsTimeout)
+	defer cancel()
+
+	if err := c.CollectArtifacts(ctx, dir, c.artifacts.paths...); err != nil {
+		// The artifacts that could be collected are still written.
+		tb.Logf("artifacts of container %s partially written to %s: %v", c.ID[:12], dir, err)
+		return
+	}
+
+	tb.Logf("artifacts of container %s written to %s", c.ID[:12], dir)
+}
+
+// sanitizeArtifactName replaces the characters of name that are not valid in file names.
+func sanitizeArtifactName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// writeLogsArtifacts writes the stdout and stderr logs of the container to dir.
+func (c *DockerContainer) writeLogsArtifacts(ctx context.Context, dir string) error {
+	rc, err := c.provider.client.ContainerLogs(ctx, c.ID, client.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("container logs: %w", err)
+	}
+	defer c.provider.Close()
+	defer rc.Close()
+
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return err
+	}
+
+	stdout, err := os.Create(filepath.Join(dir, "stdout.log"))
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	defer stdout.Close()
+
+	// The output of containers with a TTY is not multiplexed.
+	if inspect.Config.Tty {
+		_, err = io.Copy(stdout, rc)
+		return err
+	}
+
+	stderr, err := os.Create(filepath.Join(dir, "stderr.log"))
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	defer stderr.Close()
+
+	_, err = stdcopy.StdCopy(stdout, stderr, rc)
+	return err
+}
+
+// recentEvents returns the events of the container since the given time,
+// in RFC 3339 format, that the daemon still has.
+func (c *DockerContainer) recentEvents(ctx context.Context, since string) ([]ContainerEvent, error) {
+	if t, err := time.Parse(time.RFC3339Nano, since); err == nil {
+		since = fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	res := c.provider.client.Events(ctx, client.EventsListOptions{
+		Since: since,
+		Until: fmt.Sprintf("%d", time.Now().Unix()+1),
+		Filters: make(client.Filters).
+			Add("type", string(events.ContainerEventType)).
+			Add("container", c.ID),
+	})
+
+	recent := []ContainerEvent{}
+	for {
+		select {
+		case msg := <-res.Messages:
+			recent = append(recent, newContainerEvent(msg))
+		case err := <-res.Err:
+			if err == nil || errors.Is(err, io.EOF) {
+				return recent, nil
+			}
+			return recent, err
+		}
+	}
+}
+
+// copyArtifact copies the file or directory at containerPath to hostPath.
+func (c *DockerContainer) copyArtifact(ctx context.Context, containerPath string, hostPath string) error {
+	res, err := c.provider.client.ContainerStatPath(ctx, c.ID, client.ContainerStatPathOptions{
+		Path: containerPath,
+	})
+	if err != nil {
+		return fmt.Errorf("container stat path: %w", err)
+	}
+
+	if res.Stat.Mode.IsDir() {
+		return c.CopyDirFromContainer(ctx, containerPath, hostPath)
+	}
+
+	rc, err := c.CopyFileFromContainer(ctx, containerPath)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := os.MkdirAll(filepath.Dir(hostPath), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	f, err := os.Create(hostPath)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+
+	if _, err := io.Copy(f, rc); err != nil {
+		return errors.Join(fmt.Errorf("write file: %w", err), f.Close())
+	}
+
+	return f.Close()
+}
+
+// writeJSONArtifact writes v as indented JSON to path.
+func writeJSONArtifact(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	return os.WriteFile(path, b, 0o644)
+}
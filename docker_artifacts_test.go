@@ -0,0 +1,77 @@
+package testcontainers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeArtifactName(t *testing.T) {
+	require.Equal(t, "TestFoo_sub_test", sanitizeArtifactName("TestFoo/sub test"))
+	require.Equal(t, "nginx_alpine", sanitizeArtifactName("nginx:alpine"))
+}
+
+// failedTB is a [testing.TB] reporting the test as failed,
+// recording the cleanup functions instead of running them.
+type failedTB struct {
+	testing.TB
+	cleanups []func()
+}
+
+func (tb *failedTB) Failed() bool { return true }
+
+func (tb *failedTB) Cleanup(f func()) { tb.cleanups = append(tb.cleanups, f) }
+
+func TestCollectArtifacts(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := Run(ctx, nginxAlpineImage)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	// missing paths are skipped.
+	err = ctr.CollectArtifacts(ctx, dir, "/etc/nginx/nginx.conf", "/etc/nginx/conf.d", "/var/log/missing")
+	require.NoError(t, err)
+	require.NoDirExists(t, filepath.Join(dir, "files", "var"))
+
+	for _, name := range []string{"stdout.log", "stderr.log", "files/etc/nginx/nginx.conf", "files/etc/nginx/conf.d/default.conf"} {
+		require.FileExists(t, filepath.Join(dir, filepath.FromSlash(name)))
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "inspect.json"))
+	require.NoError(t, err)
+
+	var inspect struct{ ID string }
+	require.NoError(t, json.Unmarshal(b, &inspect))
+	require.Equal(t, ctr.ID, inspect.ID)
+
+	b, err = os.ReadFile(filepath.Join(dir, "events.json"))
+	require.NoError(t, err)
+
+	var recent []ContainerEvent
+	require.NoError(t, json.Unmarshal(b, &recent))
+	require.NotEmpty(t, recent)
+}
+
+func TestCleanupContainer_failureArtifacts(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	ctr, err := Run(ctx, nginxAlpineImage, WithFailureArtifacts(dir, "/etc/nginx/nginx.conf"))
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	tb := &failedTB{TB: t}
+	CleanupContainer(tb, ctr)
+	require.Len(t, tb.cleanups, 1)
+	tb.cleanups[0]()
+
+	ctrDir := filepath.Join(dir, sanitizeArtifactName(t.Name()), "nginx_alpine-"+ctr.ID[:12])
+	require.FileExists(t, filepath.Join(ctrDir, "inspect.json"))
+	require.FileExists(t, filepath.Join(ctrDir, "files", "etc", "nginx", "nginx.conf"))
+}
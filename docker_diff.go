@@ -0,0 +1,203 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// FileChangeKind is the kind of a change in the container filesystem.
+type FileChangeKind int
+
+const (
+	// FileModified is a path that existed in the image and was modified.
+	FileModified FileChangeKind = iota
+	// FileAdded is a path that didn't exist in the image.
+	FileAdded
+	// FileDeleted is a path of the image that was removed.
+	FileDeleted
+)
+
+// String returns the short representation used by docker diff: C, A or D.
+func (k FileChangeKind) String() string {
+	switch k {
+	case FileModified:
+		return "C"
+	case FileAdded:
+		return "A"
+	case FileDeleted:
+		return "D"
+	default:
+		return "?"
+	}
+}
+
+// FileChange is a change in the container filesystem.
+type FileChange struct {
+	// Path is the absolute path of the changed file or directory.
+	Path string
+
+	// Kind is the kind of the change.
+	Kind FileChangeKind
+}
+
+// String returns the change in the format used by docker diff.
+func (c FileChange) String() string {
+	return c.Kind.String() + " " + c.Path
+}
+
+// FileChanges is a list of changes in the container filesystem.
+type FileChanges []FileChange
+
+// Diff returns the changes of the container filesystem, compared to its image.
+// Changes in volumes and bind mounts are not reported.
+//
+// The changes are relative to the image, not to the start of the container, so
+// they include the writes of the entrypoint while the container starts, e.g. a
+// database initializing its data directory. To only get the later changes, take
+// a baseline once the container is ready and use [FileChanges.Since].
+//
+// As in docker diff, the parent directories of added or deleted paths are
+// reported as modified.
+func (c *DockerContainer) Diff(ctx context.Context) (FileChanges, error) {
+	res, err := c.provider.client.ContainerDiff(ctx, c.ID, client.ContainerDiffOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("container diff: %w", err)
+	}
+	defer c.provider.Close()
+
+	changes := make(FileChanges, 0, len(res.Changes))
+	for _, ch := range res.Changes {
+		changes = append(changes, FileChange{Path: ch.Path, Kind: fileChangeKind(ch.Kind)})
+	}
+
+	return changes, nil
+}
+
+// fileChangeKind converts the change type of the Docker API.
+func fileChangeKind(kind container.ChangeType) FileChangeKind {
+	switch kind {
+	case container.ChangeAdd:
+		return FileAdded
+	case container.ChangeDelete:
+		return FileDeleted
+	default:
+		return FileModified
+	}
+}
+
+// Added returns the added paths.
+func (c FileChanges) Added() FileChanges {
+	return c.Filter(func(ch FileChange) bool { return ch.Kind == FileAdded })
+}
+
+// Modified returns the modified paths.
+func (c FileChanges) Modified() FileChanges {
+	return c.Filter(func(ch FileChange) bool { return ch.Kind == FileModified })
+}
+
+// Deleted returns the deleted paths.
+func (c FileChanges) Deleted() FileChanges {
+	return c.Filter(func(ch FileChange) bool { return ch.Kind == FileDeleted })
+}
+
+// Under returns the changes of the given paths or any of their descendants.
+func (c FileChanges) Under(paths ...string) FileChanges {
+	return c.Filter(func(ch FileChange) bool { return isUnderAny(ch.Path, paths) })
+}
+
+// Outside returns the changes that are not on the given paths or their descendants.
+//
+// The parent directories of the given paths, which are reported as modified when
+// a child is added or removed, are not considered outside, so
+// Outside("/var/log") doesn't return the modification of /var.
+func (c FileChanges) Outside(paths ...string) FileChanges {
+	return c.Filter(func(ch FileChange) bool {
+		if isUnderAny(ch.Path, paths) {
+			return false
+		}
+
+		if ch.Kind == FileModified {
+			for _, p := range paths {
+				if isUnder(p, ch.Path) {
+					return false
+				}
+			}
+		}
+
+		return true
+	})
+}
+
+// Since returns the changes that are not in the baseline, a previous result of
+// [DockerContainer.Diff]. A path changed in the baseline and changed again
+// later with the same kind is not returned, as the diff doesn't track times.
+func (c FileChanges) Since(baseline FileChanges) FileChanges {
+	seen := make(map[FileChange]bool, len(baseline))
+	for _, ch := range baseline {
+		seen[FileChange{Path: path.Clean(ch.Path), Kind: ch.Kind}] = true
+	}
+
+	return c.Filter(func(ch FileChange) bool {
+		return !seen[FileChange{Path: path.Clean(ch.Path), Kind: ch.Kind}]
+	})
+}
+
+// Filter returns the changes for which keep returns true.
+func (c FileChanges) Filter(keep func(FileChange) bool) FileChanges {
+	var filtered FileChanges
+	for _, ch := range c {
+		if keep(ch) {
+			filtered = append(filtered, ch)
+		}
+	}
+
+	return filtered
+}
+
+// Paths returns the paths of the changes.
+func (c FileChanges) Paths() []string {
+	paths := make([]string, 0, len(c))
+	for _, ch := range c {
+		paths = append(paths, ch.Path)
+	}
+
+	return paths
+}
+
+// String returns the changes in the format used by docker diff, one per line.
+func (c FileChanges) String() string {
+	lines := make([]string, 0, len(c))
+	for _, ch := range c {
+		lines = append(lines, ch.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// isUnderAny reports whether p is any of the parents or one of their descendants.
+func isUnderAny(p string, parents []string) bool {
+	for _, parent := range parents {
+		if isUnder(p, parent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isUnder reports whether p is parent or one of its descendants.
+func isUnder(p string, parent string) bool {
+	p = path.Clean(p)
+	parent = path.Clean(parent)
+
+	if parent == "/" || p == parent {
+		return true
+	}
+
+	return strings.HasPrefix(p, parent+"/")
+}
@@ -0,0 +1,75 @@
+package testcontainers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileChanges(t *testing.T) {
+	changes := FileChanges{
+		{Path: "/var", Kind: FileModified},
+		{Path: "/var/log", Kind: FileModified},
+		{Path: "/var/log/app.log", Kind: FileAdded},
+		{Path: "/tmp", Kind: FileModified},
+		{Path: "/tmp/stray", Kind: FileAdded},
+		{Path: "/etc/motd", Kind: FileDeleted},
+		{Path: "/etc", Kind: FileModified},
+	}
+
+	t.Run("kind", func(t *testing.T) {
+		require.Equal(t, []string{"/var/log/app.log", "/tmp/stray"}, changes.Added().Paths())
+		require.Equal(t, []string{"/etc/motd"}, changes.Deleted().Paths())
+		require.Equal(t, []string{"/var", "/var/log", "/tmp", "/etc"}, changes.Modified().Paths())
+	})
+
+	t.Run("under", func(t *testing.T) {
+		require.Equal(t, []string{"/var/log", "/var/log/app.log"}, changes.Under("/var/log").Paths())
+		require.Len(t, changes.Under("/"), len(changes))
+		require.Empty(t, changes.Under("/var/lo"))
+	})
+
+	t.Run("outside", func(t *testing.T) {
+		require.Equal(t, []string{"/tmp", "/tmp/stray", "/etc/motd", "/etc"}, changes.Outside("/var/log").Paths())
+		require.Equal(t, []string{"/etc/motd", "/etc"}, changes.Outside("/var/log", "/tmp/").Paths())
+		require.Empty(t, changes.Outside("/"))
+	})
+
+	t.Run("since", func(t *testing.T) {
+		baseline := FileChanges{
+			{Path: "/var", Kind: FileModified},
+			{Path: "/var/log/", Kind: FileModified},
+			{Path: "/tmp/stray", Kind: FileDeleted},
+		}
+		require.Equal(t, []string{"/var/log/app.log", "/tmp", "/tmp/stray", "/etc/motd", "/etc"}, changes.Since(baseline).Paths())
+		require.Equal(t, changes, changes.Since(nil))
+	})
+
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, "C /etc\nD /etc/motd", FileChanges{changes[6], changes[5]}.String())
+	})
+}
+
+func TestContainerDiff(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := Run(ctx, nginxAlpineImage)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	// nginx writes its runtime files on start.
+	baseline, err := ctr.Diff(ctx)
+	require.NoError(t, err)
+
+	_, _, err = ctr.Exec(ctx, []string{"sh", "-c", "mkdir -p /data && echo hello > /data/out.txt && rm /etc/motd"})
+	require.NoError(t, err)
+
+	changes, err := ctr.Diff(ctx)
+	require.NoError(t, err)
+
+	require.Contains(t, changes.Added().Paths(), "/data/out.txt")
+	require.Contains(t, changes.Deleted().Paths(), "/etc/motd")
+
+	RequireNoUnexpectedWrites(ctx, t, ctr, append(baseline.Paths(), "/data", "/etc/motd")...)
+}
@@ -0,0 +1,264 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+
+	"github.com/testcontainers/testcontainers-go/internal/core"
+)
+
+// eventsReconnectDelay is the delay before subscribing again to the Docker
+// events after the subscription failed.
+const eventsReconnectDelay = time.Second
+
+// ContainerEventKind is the kind of a [ContainerEvent].
+type ContainerEventKind string
+
+const (
+	// EventStart is emitted when the container starts, including restarts.
+	EventStart ContainerEventKind = "start"
+	// EventDie is emitted when the container exits, including when it's stopped.
+	EventDie ContainerEventKind = "die"
+	// EventOOM is emitted when a process of the container is killed because the container ran out of memory.
+	EventOOM ContainerEventKind = "oom"
+	// EventHealthStatus is emitted when the health status of the container changes.
+	EventHealthStatus ContainerEventKind = "health_status"
+	// EventExecStart is emitted when a command is executed in the container.
+	EventExecStart ContainerEventKind = "exec_start"
+	// EventNetworkConnect is emitted when the container is connected to a network.
+	EventNetworkConnect ContainerEventKind = "connect"
+	// EventNetworkDisconnect is emitted when the container is disconnected from a network.
+	EventNetworkDisconnect ContainerEventKind = "disconnect"
+)
+
+// allContainerEventKinds are the kinds of events reported when no kind is requested.
+var allContainerEventKinds = []ContainerEventKind{
+	EventStart,
+	EventDie,
+	EventOOM,
+	EventHealthStatus,
+	EventExecStart,
+	EventNetworkConnect,
+	EventNetworkDisconnect,
+}
+
+// ContainerEvent is a Docker event of a container.
+type ContainerEvent struct {
+	// Kind is the kind of the event.
+	Kind ContainerEventKind
+
+	// ContainerID is the ID of the container.
+	ContainerID string
+
+	// Time is the time of the event, as reported by the daemon.
+	Time time.Time
+
+	// ExitCode is the exit code of the container, for [EventDie] events.
+	ExitCode int
+
+	// HealthStatus is the new health status of the container, for [EventHealthStatus] events,
+	// e.g. "healthy" or "unhealthy".
+	HealthStatus string
+
+	// ExecCommand is the command executed, for [EventExecStart] events.
+	ExecCommand string
+
+	// NetworkID is the ID of the network, for [EventNetworkConnect] and [EventNetworkDisconnect] events.
+	NetworkID string
+
+	// Attributes are the raw attributes of the event.
+	Attributes map[string]string
+}
+
+// newContainerEvent converts a Docker event message into a container event.
+func newContainerEvent(msg events.Message) ContainerEvent {
+	kind, detail, _ := strings.Cut(string(msg.Action), ": ")
+
+	e := ContainerEvent{
+		Kind:       ContainerEventKind(kind),
+		Time:       time.Unix(0, msg.TimeNano),
+		Attributes: msg.Actor.Attributes,
+	}
+
+	switch e.Kind {
+	case EventNetworkConnect, EventNetworkDisconnect:
+		e.ContainerID = msg.Actor.Attributes["container"]
+		e.NetworkID = msg.Actor.ID
+	default:
+		e.ContainerID = msg.Actor.ID
+	}
+
+	switch e.Kind {
+	case EventDie:
+		e.ExitCode, _ = strconv.Atoi(msg.Actor.Attributes["exitCode"])
+	case EventHealthStatus:
+		e.HealthStatus = detail
+	case EventExecStart:
+		e.ExecCommand = detail
+	}
+
+	return e
+}
+
+// Events returns a channel of the Docker events of the container, of the given
+// kinds or of all the supported ones if none is given, that happen after the call.
+// The subscription is renewed if the connection to the daemon is lost, and the
+// channel is closed when ctx is done.
+func (c *DockerContainer) Events(ctx context.Context, kinds ...ContainerEventKind) <-chan ContainerEvent {
+	return c.provider.subscribeEvents(ctx, kinds, c.ID, nil, func(e ContainerEvent) bool {
+		return e.ContainerID == c.ID
+	})
+}
+
+// Events returns a channel of the Docker events of the containers of the current
+// test session, of the given kinds or of all the supported ones if none is given,
+// that happen after the call. The subscription is renewed if the connection to
+// the daemon is lost, and the channel is closed when ctx is done.
+func (p *DockerProvider) Events(ctx context.Context, kinds ...ContainerEventKind) <-chan ContainerEvent {
+	sessionID := core.SessionID()
+
+	// Network events don't have the labels of the container,
+	// so the containers of the session are tracked.
+	sessionContainers := make(map[string]bool)
+	res, err := p.client.ContainerList(ctx, client.ContainerListOptions{
+		All:     true,
+		Filters: make(client.Filters).Add("label", core.LabelSessionID+"="+sessionID),
+	})
+	if err != nil {
+		p.Logger.Printf("events: list session containers: %v", err)
+	}
+	for _, ctr := range res.Items {
+		sessionContainers[ctr.ID] = true
+	}
+
+	track := func(msg events.Message) {
+		if msg.Type == events.ContainerEventType && msg.Actor.Attributes[core.LabelSessionID] == sessionID {
+			sessionContainers[msg.Actor.ID] = true
+		}
+	}
+
+	return p.subscribeEvents(ctx, kinds, "", track, func(e ContainerEvent) bool {
+		return sessionContainers[e.ContainerID]
+	})
+}
+
+// subscribeEvents streams the container events of the given kinds accepted by match,
+// until ctx is done. The events are filtered by the daemon on containerID, if not empty.
+// Each event message is passed to track, if not nil, before being converted and matched.
+// The subscription is renewed if the connection is lost.
+func (p *DockerProvider) subscribeEvents(ctx context.Context, kinds []ContainerEventKind, containerID string, track func(events.Message), match func(ContainerEvent) bool) <-chan ContainerEvent {
+	if len(kinds) == 0 {
+		kinds = allContainerEventKinds
+	}
+
+	var networkEvents bool
+	filters := make(client.Filters).Add("type", string(events.ContainerEventType))
+	for _, k := range kinds {
+		if k == EventNetworkConnect || k == EventNetworkDisconnect {
+			networkEvents = true
+			filters.Add("type", string(events.NetworkEventType))
+		}
+		filters.Add("event", string(k))
+	}
+
+	// The container filter of the daemon matches the actor of the event, which is
+	// the network for the network events, so they are only filtered by match.
+	if containerID != "" && !networkEvents {
+		filters.Add("container", containerID)
+	}
+
+	if track != nil {
+		// Track the containers as soon as they are created or started.
+		filters.Add("event", string(EventStart), string(events.ActionCreate))
+	}
+
+	wanted := make(map[ContainerEventKind]bool, len(kinds))
+	for _, k := range kinds {
+		wanted[k] = true
+	}
+
+	// The last event received, to resume from it when subscribing again,
+	// or the start of the subscription if none was received yet.
+	var last int64
+	start := time.Now().UnixNano()
+	subscribe := func(first bool) client.EventsResult {
+		var since string
+		if !first {
+			from := last
+			if from == 0 {
+				from = start
+			}
+			since = fmt.Sprintf("%d.%09d", from/int64(time.Second), from%int64(time.Second))
+		}
+
+		return p.client.Events(ctx, client.EventsListOptions{
+			Since:   since,
+			Filters: filters,
+		})
+	}
+
+	// The first subscription is synchronous, so no event after the call is missed.
+	res := subscribe(true)
+	ch := make(chan ContainerEvent)
+
+	go func() {
+		defer close(ch)
+
+		for {
+		loop:
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case err := <-res.Err:
+					if ctx.Err() != nil {
+						return
+					}
+					if !errors.Is(err, io.EOF) {
+						p.Logger.Printf("events: %v, subscribing again", err)
+					}
+					break loop
+				case msg := <-res.Messages:
+					if msg.TimeNano <= last {
+						// Already received before subscribing again.
+						continue
+					}
+					last = msg.TimeNano
+
+					if track != nil {
+						track(msg)
+					}
+
+					e := newContainerEvent(msg)
+					if !wanted[e.Kind] || !match(e) {
+						continue
+					}
+
+					select {
+					case ch <- e:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(eventsReconnectDelay):
+			}
+
+			res = subscribe(false)
+		}
+	}()
+
+	return ch
+}
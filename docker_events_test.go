@@ -0,0 +1,101 @@
+package testcontainers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContainerEvent(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	tests := []struct {
+		name string
+		msg  events.Message
+		want ContainerEvent
+	}{
+		{
+			name: "die",
+			msg: events.Message{
+				Type:   events.ContainerEventType,
+				Action: events.ActionDie,
+				Actor:  events.Actor{ID: "ctr", Attributes: map[string]string{"exitCode": "137"}},
+			},
+			want: ContainerEvent{Kind: EventDie, ContainerID: "ctr", ExitCode: 137},
+		},
+		{
+			name: "health-status",
+			msg: events.Message{
+				Type:   events.ContainerEventType,
+				Action: events.ActionHealthStatusUnhealthy,
+				Actor:  events.Actor{ID: "ctr"},
+			},
+			want: ContainerEvent{Kind: EventHealthStatus, ContainerID: "ctr", HealthStatus: "unhealthy"},
+		},
+		{
+			name: "exec-start",
+			msg: events.Message{
+				Type:   events.ContainerEventType,
+				Action: events.ActionExecStart + ": sh -c echo hello",
+				Actor:  events.Actor{ID: "ctr"},
+			},
+			want: ContainerEvent{Kind: EventExecStart, ContainerID: "ctr", ExecCommand: "sh -c echo hello"},
+		},
+		{
+			name: "network-connect",
+			msg: events.Message{
+				Type:   events.NetworkEventType,
+				Action: events.ActionConnect,
+				Actor:  events.Actor{ID: "net", Attributes: map[string]string{"container": "ctr"}},
+			},
+			want: ContainerEvent{Kind: EventNetworkConnect, ContainerID: "ctr", NetworkID: "net"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.msg.TimeNano = at.UnixNano()
+			tt.want.Time = time.Unix(0, at.UnixNano())
+			tt.want.Attributes = tt.msg.Actor.Attributes
+
+			require.Equal(t, tt.want, newContainerEvent(tt.msg))
+		})
+	}
+}
+
+func TestContainerEvents(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := Run(ctx, nginxAlpineImage)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	eventsCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	containerEvents := ctr.Events(eventsCtx, EventStart, EventDie, EventExecStart)
+	sessionEvents := ctr.provider.Events(eventsCtx, EventStart)
+
+	_, _, err = ctr.Exec(ctx, []string{"true"})
+	require.NoError(t, err)
+
+	require.NoError(t, ctr.Stop(ctx, nil))
+	require.NoError(t, ctr.Start(ctx))
+
+	var kinds []ContainerEventKind
+	for e := range containerEvents {
+		require.Equal(t, ctr.ID, e.ContainerID)
+		kinds = append(kinds, e.Kind)
+		if e.Kind == EventStart {
+			break
+		}
+	}
+	require.Equal(t, []ContainerEventKind{EventExecStart, EventDie, EventStart}, kinds)
+
+	e := <-sessionEvents
+	require.Equal(t, EventStart, e.Kind)
+	require.Equal(t, ctr.ID, e.ContainerID)
+}
@@ -0,0 +1,514 @@
+package testcontainers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// execPIDDir is the directory, inside the container, where the PID of the processes
+// started with [DockerContainer.ExecStream] is recorded. The Docker API only reports
+// the PID in the host namespace, which cannot be used to signal the process from
+// inside the container.
+const execPIDDir = "/tmp"
+
+// execShell is the shell recording the PID of the processes, if the container has one.
+const execShell = "/bin/sh"
+
+// errExecFinished is returned when trying to signal a process that already exited.
+var errExecFinished = errors.New("exec process already finished")
+
+// ExecSession represents a process running inside a container, started with
+// [DockerContainer.ExecStream]. Its standard streams are connected while the
+// process runs, so it can be driven interactively.
+//
+// As with [os/exec], Stdout and Stderr must be fully consumed before calling
+// [ExecSession.Wait], otherwise the process can block writing its output.
+type ExecSession struct {
+	// Stdin is connected to the standard input of the process.
+	// Closing it sends EOF to the process.
+	Stdin io.WriteCloser
+
+	// Stdout streams the standard output of the process.
+	// When a TTY is attached it carries the combined output of the process.
+	Stdout io.Reader
+
+	// Stderr streams the standard error of the process.
+	// It is always empty when a TTY is attached.
+	Stderr io.Reader
+
+	id      string
+	pidFile string // empty if the container has no shell
+	ctr     *DockerContainer
+	ctx     context.Context
+	hijack  *client.HijackedResponse
+
+	// detachErr is set when ctx is done and the process couldn't be killed.
+	detachMtx sync.Mutex
+	detachErr error
+
+	// done is closed when the output of the process has been drained.
+	done chan struct{}
+
+	waitOnce sync.Once
+	exitCode int
+	waitErr  error
+}
+
+// ExecStream starts a command in the container and returns as soon as it's running,
+// with its stdin, stdout and stderr streams connected to the returned [ExecSession].
+// Use [ExecSession.Wait] to wait for the process to exit and get its exit code.
+//
+// Use [tcexec.WithTTY] and [tcexec.WithConsoleSize] to allocate a pseudo-TTY, which is
+// needed by REPL-like programs such as psql or redis-cli.
+//
+// Use [ExecSession.Signal] to send signals to the process. The process is killed if
+// ctx is done before it exits.
+//
+// If the container has a shell, the process is started by it to record its PID, then
+// replaced by the command. Otherwise the command runs as is, e.g. in distroless
+// images, and the signals are sent from a privileged sidecar container sharing the
+// PID namespace of the daemon, with the PID reported by the Docker API.
+func (c *DockerContainer) ExecStream(ctx context.Context, cmd []string, options ...tcexec.ProcessOption) (*ExecSession, error) {
+	cli := c.provider.client
+
+	var pidFile string
+	if _, err := cli.ContainerStatPath(ctx, c.ID, client.ContainerStatPathOptions{Path: execShell}); err == nil {
+		pidFile = fmt.Sprintf("%s/.testcontainers-exec-%s.pid", execPIDDir, uuid.NewString())
+		cmd = execWithPIDFile(pidFile, cmd)
+	}
+
+	processOptions := tcexec.NewProcessOptions(cmd)
+	for _, o := range options {
+		o.Apply(processOptions)
+	}
+	processOptions.ExecConfig.AttachStdin = true
+
+	response, err := cli.ExecCreate(ctx, c.ID, processOptions.ExecConfig)
+	if err != nil {
+		return nil, fmt.Errorf("container exec create: %w", err)
+	}
+
+	hijack, err := cli.ExecAttach(ctx, response.ID, client.ExecAttachOptions{
+		TTY:         processOptions.ExecConfig.TTY,
+		ConsoleSize: processOptions.ExecConfig.ConsoleSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("container exec attach: %w", err)
+	}
+
+	stdoutR, stdoutW := io.Pipe()
+	stderrR, stderrW := io.Pipe()
+
+	s := &ExecSession{
+		Stdin:   &execStdin{hijack: &hijack.HijackedResponse},
+		Stdout:  stdoutR,
+		Stderr:  stderrR,
+		id:      response.ID,
+		pidFile: pidFile,
+		ctr:     c,
+		ctx:     context.WithoutCancel(ctx),
+		hijack:  &hijack.HijackedResponse,
+		done:    make(chan struct{}),
+	}
+
+	go func(tty bool) {
+		defer close(s.done)
+
+		var err error
+		if tty {
+			_, err = io.Copy(stdoutW, hijack.Reader)
+		} else {
+			_, err = stdcopy.StdCopy(stdoutW, stderrW, hijack.Reader)
+		}
+
+		// A nil error closes the pipes with io.EOF.
+		stdoutW.CloseWithError(err)
+		stderrW.CloseWithError(err)
+	}(processOptions.ExecConfig.TTY)
+
+	go func() {
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			s.kill(context.Cause(ctx))
+			s.hijack.Close()
+		}
+	}()
+
+	return s, nil
+}
+
+// ID returns the ID of the exec instance in the Docker daemon.
+func (s *ExecSession) ID() string {
+	return s.id
+}
+
+// kill kills the process once the context of the session is done with cause,
+// recording the error returned by [ExecSession.Wait] if it may still be running.
+func (s *ExecSession) kill(cause error) {
+	killCtx, cancel := context.WithTimeout(s.ctx, time.Minute)
+	defer cancel()
+
+	errKill := s.Signal(killCtx, syscall.SIGKILL)
+	if errKill == nil || errors.Is(errKill, errExecFinished) {
+		return
+	}
+
+	s.detachMtx.Lock()
+	s.detachErr = fmt.Errorf("context done: %w, kill: %w", cause, errKill)
+	s.detachMtx.Unlock()
+}
+
+// Wait waits for the process to exit, returning its exit code.
+// It closes the connection to the process streams, so it must be called
+// after Stdout and Stderr have been fully read.
+func (s *ExecSession) Wait() (int, error) {
+	s.waitOnce.Do(func() {
+		<-s.done
+		s.hijack.Close()
+
+		s.detachMtx.Lock()
+		s.waitErr = s.detachErr
+		s.detachMtx.Unlock()
+		if s.waitErr != nil {
+			// The process may still be running.
+			return
+		}
+
+		s.exitCode, s.waitErr = s.ctr.execExitCode(s.ctx, s.id)
+
+		if s.pidFile != "" {
+			// The PID file is best effort: it lives in a temporary directory.
+			_, _, _ = s.ctr.Exec(s.ctx, []string{"rm", "-f", s.pidFile}, tcexec.WithUser("0"))
+		}
+	})
+
+	return s.exitCode, s.waitErr
+}
+
+// Signal sends sig to the process. It returns an error if the process already exited.
+//
+// Without a shell in the container, the signal is sent from a sidecar container,
+// see [DockerContainer.ExecStream], which takes longer.
+func (s *ExecSession) Signal(ctx context.Context, sig syscall.Signal) error {
+	select {
+	case <-s.done:
+		return errExecFinished
+	default:
+	}
+
+	if s.pidFile == "" {
+		pid, err := s.hostPID(ctx)
+		if err != nil {
+			return err
+		}
+
+		return signalHostPID(ctx, pid, sig)
+	}
+
+	code, r, err := s.ctr.Exec(ctx, signalCommand(s.pidFile, sig), tcexec.WithUser("0"), tcexec.Multiplexed())
+	if err != nil {
+		return fmt.Errorf("exec kill: %w", err)
+	}
+
+	if code != 0 {
+		out, _ := io.ReadAll(r)
+		return fmt.Errorf("exec kill: exit code %d: %s", code, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// hostPID returns the PID of the process in the namespace of the daemon, waiting
+// for the exec instance to be started.
+func (s *ExecSession) hostPID(ctx context.Context) (int, error) {
+	for {
+		res, err := s.ctr.provider.client.ExecInspect(ctx, s.id, client.ExecInspectOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("container exec inspect: %w", err)
+		}
+
+		if res.PID != 0 {
+			if !res.Running {
+				return 0, errExecFinished
+			}
+
+			return res.PID, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("exec process not started: %w", ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// signalHostPID sends sig to the process with the PID in the namespace of the daemon,
+// from a privileged sidecar container sharing that namespace.
+func signalHostPID(ctx context.Context, pid int, sig syscall.Signal) (err error) {
+	sidecar, err := Run(ctx, networkToolsImage,
+		WithEntrypoint("kill", "-"+strconv.Itoa(int(sig)), strconv.Itoa(pid)),
+		WithHostConfigModifier(func(hostConfig *container.HostConfig) {
+			hostConfig.PidMode = "host"
+			hostConfig.Privileged = true
+		}),
+		WithWaitStrategy(wait.ForExit()),
+	)
+	defer func() {
+		if errTerminate := TerminateContainer(sidecar); errTerminate != nil {
+			err = errors.Join(err, fmt.Errorf("terminate signal sidecar: %w", errTerminate))
+		}
+	}()
+	if err != nil {
+		return fmt.Errorf("run signal sidecar: %w", err)
+	}
+
+	state, err := sidecar.State(ctx)
+	if err != nil {
+		return fmt.Errorf("signal sidecar state: %w", err)
+	}
+
+	if state.ExitCode != 0 {
+		var out bytes.Buffer
+		if logs, errLogs := sidecar.Logs(ctx); errLogs == nil {
+			_, _ = stdcopy.StdCopy(&out, &out, logs)
+			logs.Close()
+		}
+		return fmt.Errorf("kill %d: exit code %d: %s", pid, state.ExitCode, strings.TrimSpace(out.String()))
+	}
+
+	return nil
+}
+
+// Resize changes the size of the pseudo-TTY of the process, in rows and columns.
+// The process must have been started with [tcexec.WithTTY].
+func (s *ExecSession) Resize(ctx context.Context, height, width uint) error {
+	_, err := s.ctr.provider.client.ExecResize(ctx, s.id, client.ExecResizeOptions{
+		Height: height,
+		Width:  width,
+	})
+	if err != nil {
+		return fmt.Errorf("container exec resize: %w", err)
+	}
+
+	return nil
+}
+
+// execStdin is the write side of the hijacked connection of an exec instance.
+type execStdin struct {
+	hijack *client.HijackedResponse
+}
+
+// Write writes p to the standard input of the process.
+func (w *execStdin) Write(p []byte) (int, error) {
+	return w.hijack.Conn.Write(p)
+}
+
+// Close closes the standard input of the process, keeping its output open.
+func (w *execStdin) Close() error {
+	return w.hijack.CloseWrite()
+}
+
+// execWithPIDFile wraps cmd in a shell that records its PID in pidFile
+// before replacing itself with cmd, so the PID is the one of cmd.
+func execWithPIDFile(pidFile string, cmd []string) []string {
+	wrapped := make([]string, 0, len(cmd)+4)
+	wrapped = append(wrapped, execShell, "-c", `echo $$ > "$0" && exec "$@"`, pidFile)
+	return append(wrapped, cmd...)
+}
+
+// signalCommand returns the command sending sig to the process whose PID is
+// recorded in pidFile. The wrapper shell records the PID once the exec instance
+// is started, so the command waits for the file to be written, up to 10 seconds.
+func signalCommand(pidFile string, sig syscall.Signal) []string {
+	script := `i=0
+while [ ! -s "$0" ]; do
+  i=$((i+1))
+  [ "$i" -gt 100 ] && { echo "PID file $0 not written" >&2; exit 1; }
+  sleep 0.1
+done
+kill -` + strconv.Itoa(int(sig)) + ` "$(cat "$0")"`
+
+	return []string{execShell, "-c", script, pidFile}
+}
+
+// execExitCode polls the exec instance until it is not running, returning its exit code.
+func (c *DockerContainer) execExitCode(ctx context.Context, execID string) (int, error) {
+	for {
+		execResp, err := c.provider.client.ExecInspect(ctx, execID, client.ExecInspectOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("container exec inspect: %w", err)
+		}
+
+		if !execResp.Running {
+			return execResp.ExitCode, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// ExecProcess represents a long-lived process running in the background inside a
+// container, started with [DockerContainer.StartProcess].
+type ExecProcess struct {
+	session *ExecSession
+
+	// done is closed when the output of the process has been sent to the consumers.
+	done chan struct{}
+}
+
+// processLogConsumers is a [tcexec.ProcessOption] carrying the log consumers of a
+// background process. It doesn't modify the exec configuration.
+type processLogConsumers []LogConsumer
+
+// Apply implements [tcexec.ProcessOption].
+func (processLogConsumers) Apply(*tcexec.ProcessOptions) {}
+
+// WithProcessLogConsumers returns a [tcexec.ProcessOption] that sends the stdout and
+// stderr of a process started with [DockerContainer.StartProcess] to the consumers.
+func WithProcessLogConsumers(consumers ...LogConsumer) tcexec.ProcessOption {
+	return processLogConsumers(consumers)
+}
+
+// StartProcess starts a command in the background inside the container, returning
+// as soon as it's running. The output of the process is sent to the log consumers
+// passed with [WithProcessLogConsumers], and discarded otherwise.
+//
+// The process can be stopped with [ExecProcess.Stop], as [DockerContainer.ExecStream]
+// describes. Processes still running are stopped when the container is terminated.
+func (c *DockerContainer) StartProcess(ctx context.Context, cmd []string, options ...tcexec.ProcessOption) (*ExecProcess, error) {
+	var consumers []LogConsumer
+	for _, o := range options {
+		if lc, ok := o.(processLogConsumers); ok {
+			consumers = append(consumers, lc...)
+		}
+	}
+
+	// The process outlives the call, so it must not be killed when ctx is done.
+	session, err := c.ExecStream(context.WithoutCancel(ctx), cmd, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Background processes don't read from stdin.
+	if err := session.Stdin.Close(); err != nil {
+		session.hijack.Close()
+		return nil, fmt.Errorf("close stdin: %w", err)
+	}
+
+	p := &ExecProcess{
+		session: session,
+		done:    make(chan struct{}),
+	}
+
+	// Registered before its output is consumed, so that it's removed once it exits.
+	c.processesMtx.Lock()
+	c.processes = append(c.processes, p)
+	c.processesMtx.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		w := newLogConsumerWriter(StdoutLog, consumers)
+		io.Copy(w, session.Stdout) //nolint:errcheck // The process is gone.
+		w.Flush()
+	}()
+	go func() {
+		defer wg.Done()
+		w := newLogConsumerWriter(StderrLog, consumers)
+		io.Copy(w, session.Stderr) //nolint:errcheck // The process is gone.
+		w.Flush()
+	}()
+	go func() {
+		wg.Wait()
+		c.removeProcess(p)
+		close(p.done)
+	}()
+
+	return p, nil
+}
+
+// removeProcess releases the reference to the process once its output is drained,
+// as the process exited, or was stopped, so that it's not closed again on terminate.
+func (c *DockerContainer) removeProcess(p *ExecProcess) {
+	c.processesMtx.Lock()
+	defer c.processesMtx.Unlock()
+
+	c.processes = slices.DeleteFunc(c.processes, func(other *ExecProcess) bool {
+		return other == p
+	})
+}
+
+// ID returns the ID of the exec instance in the Docker daemon.
+func (p *ExecProcess) ID() string {
+	return p.session.ID()
+}
+
+// Status returns the status of the process, as reported by the exec inspect endpoint.
+func (p *ExecProcess) Status(ctx context.Context) (client.ExecInspectResult, error) {
+	res, err := p.session.ctr.provider.client.ExecInspect(ctx, p.session.id, client.ExecInspectOptions{})
+	if err != nil {
+		return client.ExecInspectResult{}, fmt.Errorf("container exec inspect: %w", err)
+	}
+
+	return res, nil
+}
+
+// Wait waits for the process to exit, returning its exit code.
+func (p *ExecProcess) Wait() (int, error) {
+	<-p.done
+	return p.session.Wait()
+}
+
+// Stop kills the process and waits for it to exit.
+// It's a no-op if the process already exited.
+func (p *ExecProcess) Stop(ctx context.Context) error {
+	if err := p.session.Signal(ctx, syscall.SIGKILL); err != nil && !errors.Is(err, errExecFinished) {
+		return fmt.Errorf("signal: %w", err)
+	}
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	_, err := p.session.Wait()
+	return err
+}
+
+// closeProcesses releases the connections to the background processes of the
+// container. It's meant to be called once the container is stopped, as the
+// processes don't outlive it.
+func (c *DockerContainer) closeProcesses() {
+	c.processesMtx.Lock()
+	defer c.processesMtx.Unlock()
+
+	for _, p := range c.processes {
+		p.session.hijack.Close()
+	}
+	c.processes = nil
+}
@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"slices"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	tcexec "github.com/testcontainers/testcontainers-go/exec"
@@ -116,3 +121,260 @@ func TestExecWithNonMultiplexedResponse(t *testing.T) {
 	require.Equal(t, "stdout\n", stdout.String())
 	require.Equal(t, "stderr\n", stderr.String())
 }
+
+func TestExecStream(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := Run(ctx, nginxAlpineImage)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	t.Run("stdin", func(t *testing.T) {
+		session, err := ctr.ExecStream(ctx, []string{"cat"})
+		require.NoError(t, err)
+
+		_, err = io.WriteString(session.Stdin, "hello\n")
+		require.NoError(t, err)
+		require.NoError(t, session.Stdin.Close())
+
+		out, err := io.ReadAll(session.Stdout)
+		require.NoError(t, err)
+		require.Equal(t, "hello\n", string(out))
+
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Zero(t, code)
+	})
+
+	t.Run("separate-streams", func(t *testing.T) {
+		session, err := ctr.ExecStream(ctx, []string{"sh", "-c", "echo stdout; echo stderr >&2; exit 3"})
+		require.NoError(t, err)
+		require.NoError(t, session.Stdin.Close())
+
+		var stderr bytes.Buffer
+		errDone := make(chan error, 1)
+		go func() {
+			_, err := io.Copy(&stderr, session.Stderr)
+			errDone <- err
+		}()
+
+		stdout, err := io.ReadAll(session.Stdout)
+		require.NoError(t, err)
+		require.NoError(t, <-errDone)
+
+		require.Equal(t, "stdout\n", string(stdout))
+		require.Equal(t, "stderr\n", stderr.String())
+
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 3, code)
+	})
+
+	t.Run("tty", func(t *testing.T) {
+		session, err := ctr.ExecStream(ctx, []string{"sh", "-c", "stty size; echo stderr >&2"}, tcexec.WithTTY(), tcexec.WithConsoleSize(24, 80))
+		require.NoError(t, err)
+
+		out, err := io.ReadAll(session.Stdout)
+		require.NoError(t, err)
+		require.Contains(t, string(out), "24 80")
+		require.Contains(t, string(out), "stderr")
+
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Zero(t, code)
+	})
+
+	t.Run("signal", func(t *testing.T) {
+		session, err := ctr.ExecStream(ctx, []string{"sleep", "300"})
+		require.NoError(t, err)
+
+		// the signal waits for the shell wrapper to record the PID.
+		require.NoError(t, session.Signal(ctx, syscall.SIGTERM))
+
+		_, err = io.Copy(io.Discard, session.Stdout)
+		require.NoError(t, err)
+
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 143, code)
+
+		require.ErrorIs(t, session.Signal(ctx, syscall.SIGTERM), errExecFinished)
+	})
+
+	t.Run("context-cancel", func(t *testing.T) {
+		cancelCtx, cancel := context.WithCancel(ctx)
+
+		session, err := ctr.ExecStream(cancelCtx, []string{"sleep", "300"})
+		require.NoError(t, err)
+
+		cancel()
+
+		_, _ = io.Copy(io.Discard, session.Stdout)
+
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 137, code)
+	})
+
+	t.Run("signal/stdin", func(t *testing.T) {
+		session, err := ctr.ExecStream(ctx, []string{"cat"})
+		require.NoError(t, err)
+
+		require.NoError(t, session.Signal(ctx, syscall.SIGTERM))
+
+		_, err = io.Copy(io.Discard, session.Stdout)
+		require.NoError(t, err)
+
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 143, code)
+	})
+}
+
+func TestExecStream_noShell(t *testing.T) {
+	ctx := context.Background()
+
+	// the image has no /bin/sh, only the busybox binaries.
+	ctr, err := Run(ctx, "gcr.io/distroless/static-debian12:debug-nonroot",
+		WithEntrypoint("/busybox/sleep"),
+		WithCmd("300"),
+	)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	session, err := ctr.ExecStream(ctx, []string{"/busybox/echo", "hello"})
+	require.NoError(t, err)
+
+	out, err := io.ReadAll(session.Stdout)
+	require.NoError(t, err)
+	require.Equal(t, "hello\n", string(out))
+
+	code, err := session.Wait()
+	require.NoError(t, err)
+	require.Zero(t, code)
+
+	t.Run("signal", func(t *testing.T) {
+		// without a shell, the signal is sent to the host PID of the process.
+		session, err := ctr.ExecStream(ctx, []string{"/busybox/sleep", "300"})
+		require.NoError(t, err)
+
+		require.NoError(t, session.Signal(ctx, syscall.SIGTERM))
+
+		_, err = io.Copy(io.Discard, session.Stdout)
+		require.NoError(t, err)
+
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 143, code)
+	})
+
+	t.Run("context-cancel", func(t *testing.T) {
+		cancelCtx, cancel := context.WithCancel(ctx)
+
+		session, err := ctr.ExecStream(cancelCtx, []string{"/busybox/sleep", "300"})
+		require.NoError(t, err)
+
+		cancel()
+
+		_, _ = io.Copy(io.Discard, session.Stdout)
+
+		code, err := session.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 137, code)
+	})
+}
+
+func TestStartProcess(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := Run(ctx, nginxAlpineImage)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	t.Run("stop", func(t *testing.T) {
+		consumer := &bufferLogConsumer{}
+
+		p, err := ctr.StartProcess(ctx, []string{"sh", "-c", "while true; do echo tick; sleep 0.1; done"}, WithProcessLogConsumers(consumer))
+		require.NoError(t, err)
+
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			require.Contains(c, consumer.String(), "tick\n")
+		}, 5*time.Second, 100*time.Millisecond)
+
+		status, err := p.Status(ctx)
+		require.NoError(t, err)
+		require.True(t, status.Running)
+
+		require.NoError(t, p.Stop(ctx))
+
+		status, err = p.Status(ctx)
+		require.NoError(t, err)
+		require.False(t, status.Running)
+		require.Equal(t, 137, status.ExitCode)
+
+		// stopping an exited process is a no-op.
+		require.NoError(t, p.Stop(ctx))
+
+		ctr.processesMtx.Lock()
+		processes := slices.Clone(ctr.processes)
+		ctr.processesMtx.Unlock()
+		require.NotContains(t, processes, p)
+	})
+
+	t.Run("exit", func(t *testing.T) {
+		consumer := &bufferLogConsumer{}
+
+		p, err := ctr.StartProcess(ctx, []string{"sh", "-c", "echo done >&2; exit 2"}, WithProcessLogConsumers(consumer))
+		require.NoError(t, err)
+
+		code, err := p.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 2, code)
+		require.Equal(t, "done\n", consumer.String())
+
+		ctr.processesMtx.Lock()
+		processes := slices.Clone(ctr.processes)
+		ctr.processesMtx.Unlock()
+		require.NotContains(t, processes, p)
+	})
+}
+
+func TestStartProcess_terminate(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := Run(ctx, nginxAlpineImage)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	p, err := ctr.StartProcess(ctx, []string{"tail", "-f", "/dev/null"})
+	require.NoError(t, err)
+
+	require.NoError(t, ctr.Terminate(ctx))
+
+	select {
+	case <-p.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("process output not released after terminate")
+	}
+}
+
+// bufferLogConsumer accumulates the content of all the logs it receives.
+// It is safe to use concurrently.
+type bufferLogConsumer struct {
+	mtx sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *bufferLogConsumer) Accept(l Log) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.buf.Write(l.Content)
+}
+
+func (b *bufferLogConsumer) String() string {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.buf.String()
+}
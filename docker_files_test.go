@@ -2,9 +2,11 @@ package testcontainers_test
 
 import (
 	"context"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/stretchr/testify/require"
@@ -160,3 +162,62 @@ func TestCopyDirectoryToRunningContainerAsDir(t *testing.T) {
 	require.NoError(t, err)
 	// }
 }
+
+func TestCopyDirFromContainer(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, testBashImage, testcontainers.WithCmd("sleep", "infinity"))
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	_, _, err = ctr.Exec(ctx, []string{"sh", "-c", "mkdir -p /tmp/report/sub && echo a > /tmp/report/a.txt && echo b > /tmp/report/sub/b.txt"})
+	require.NoError(t, err)
+
+	// copyDirFromContainer {
+	dst := t.TempDir()
+	err = ctr.CopyDirFromContainer(ctx, "/tmp/report", dst)
+	// }
+	require.NoError(t, err)
+
+	b, err := os.ReadFile(filepath.Join(dst, "a.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "a\n", string(b))
+
+	b, err = os.ReadFile(filepath.Join(dst, "sub", "b.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "b\n", string(b))
+
+	t.Run("not-a-directory", func(t *testing.T) {
+		err := ctr.CopyDirFromContainer(ctx, "/tmp/report/a.txt", t.TempDir())
+		require.Error(t, err)
+	})
+}
+
+func TestContainerFS(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, testBashImage, testcontainers.WithCmd("sleep", "infinity"))
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	_, _, err = ctr.Exec(ctx, []string{"sh", "-c", "mkdir -p /tmp/report/sub && echo a > /tmp/report/a.txt && echo b > /tmp/report/sub/b.txt && ln -s report/a.txt /tmp/link"})
+	require.NoError(t, err)
+
+	// containerFS {
+	fsys := ctr.FS(ctx, "/tmp/report")
+
+	b, err := fs.ReadFile(fsys, "sub/b.txt")
+	// }
+	require.NoError(t, err)
+	require.Equal(t, "b\n", string(b))
+
+	// symbolic links are followed
+	b, err = fs.ReadFile(ctr.FS(ctx, "/tmp"), "link")
+	require.NoError(t, err)
+	require.Equal(t, "a\n", string(b))
+
+	_, err = fs.Stat(fsys, "missing.txt")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	require.NoError(t, fstest.TestFS(fsys, "a.txt", "sub/b.txt"))
+}
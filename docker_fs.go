@@ -0,0 +1,355 @@
+package testcontainers
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+
+	"github.com/testcontainers/testcontainers-go/log"
+)
+
+// maxSymlinks is the maximum number of symbolic links followed when resolving a path
+// in the container filesystem, matching the limit used by Linux.
+const maxSymlinks = 40
+
+// Implement interfaces
+var (
+	_ fs.FS          = (*containerFS)(nil)
+	_ fs.StatFS      = (*containerFS)(nil)
+	_ fs.ReadDirFile = (*containerDir)(nil)
+)
+
+// CopyDirFromContainer copies the contents of the containerPath directory of the
+// container into hostPath, which is created if it does not exist.
+// Symbolic links are skipped.
+func (c *DockerContainer) CopyDirFromContainer(ctx context.Context, containerPath string, hostPath string) error {
+	r, err := c.provider.client.CopyFromContainer(ctx, c.ID, client.CopyFromContainerOptions{
+		SourcePath: containerPath,
+	})
+	if err != nil {
+		return fmt.Errorf("copy from container: %w", err)
+	}
+	defer c.provider.Close()
+	defer r.Content.Close()
+
+	if !r.Stat.Mode.IsDir() {
+		return fmt.Errorf("path %s is not a directory", containerPath)
+	}
+
+	if err := os.MkdirAll(hostPath, 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	return untarDir(tar.NewReader(r.Content), r.Stat.Name, hostPath)
+}
+
+// untarDir extracts the entries of tr under the base directory into dst.
+// Entries escaping dst and symbolic links are skipped.
+func untarDir(tr *tar.Reader, base string, dst string) error {
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("reading tar archive: %w", err)
+		}
+
+		rel, ok := archiveRelPath(base, hdr.Name)
+		if !ok || rel == "." {
+			continue
+		}
+
+		if !filepath.IsLocal(rel) {
+			log.Printf(">> skipping entry outside the target directory: %s\n", hdr.Name)
+			continue
+		}
+
+		target := filepath.Join(dst, filepath.FromSlash(rel))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, hdr.FileInfo().Mode().Perm()|0o700); err != nil {
+				return fmt.Errorf("create directory: %w", err)
+			}
+		case tar.TypeReg:
+			if err := untarFile(tr, target, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		default:
+			log.Printf(">> skipping non regular file: %s\n", hdr.Name)
+		}
+	}
+}
+
+// untarFile writes the content of the current entry of tr to target.
+func untarFile(tr *tar.Reader, target string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+
+	if _, err := io.Copy(f, tr); err != nil {
+		return errors.Join(fmt.Errorf("write file: %w", err), f.Close())
+	}
+
+	return f.Close()
+}
+
+// archiveRelPath returns the path of the archive entry name relative to the base
+// directory of the archive, which is the first element of every entry.
+func archiveRelPath(base string, name string) (string, bool) {
+	name = strings.TrimSuffix(name, "/")
+	if name == base {
+		return ".", true
+	}
+
+	rel, ok := strings.CutPrefix(name, base+"/")
+	if !ok {
+		return "", false
+	}
+
+	return path.Clean(rel), true
+}
+
+// FS returns a read-only [fs.FS] for the container filesystem rooted at root,
+// backed by the Docker archive API. It allows the use of [fs.WalkDir],
+// [fs.ReadFile] and [testing/fstest] directly against the container contents.
+//
+// Listing a directory transfers the archive of its whole tree, so it can be slow
+// for big directories. All the operations use ctx.
+func (c *DockerContainer) FS(ctx context.Context, root string) fs.FS {
+	return &containerFS{
+		ctx:  ctx,
+		ctr:  c,
+		root: root,
+	}
+}
+
+// containerFS is an [fs.FS] backed by the Docker archive API.
+type containerFS struct {
+	ctx  context.Context
+	ctr  *DockerContainer
+	root string
+}
+
+// Open implements [fs.FS].
+func (cfs *containerFS) Open(name string) (fs.File, error) {
+	stat, p, err := cfs.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := cfs.ctr.provider.client.CopyFromContainer(cfs.ctx, cfs.ctr.ID, client.CopyFromContainerOptions{
+		SourcePath: p,
+	})
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+	defer cfs.ctr.provider.Close()
+
+	info := pathStatInfo{stat: stat, name: path.Base(name)}
+	tr := tar.NewReader(r.Content)
+
+	if stat.Mode.IsDir() {
+		defer r.Content.Close()
+
+		entries, err := readDirEntries(tr, stat.Name)
+		if err != nil {
+			return nil, pathError("open", name, err)
+		}
+
+		return &containerDir{info: info, entries: entries}, nil
+	}
+
+	if _, err := tr.Next(); err != nil {
+		r.Content.Close()
+		return nil, pathError("open", name, err)
+	}
+
+	return &containerFile{info: info, tr: tr, rc: r.Content}, nil
+}
+
+// Stat implements [fs.StatFS].
+func (cfs *containerFS) Stat(name string) (fs.FileInfo, error) {
+	stat, _, err := cfs.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return pathStatInfo{stat: stat, name: path.Base(name)}, nil
+}
+
+// resolve returns the stat of name, following symbolic links,
+// and the resolved path in the container.
+func (cfs *containerFS) resolve(op string, name string) (container.PathStat, string, error) {
+	if !fs.ValidPath(name) {
+		return container.PathStat{}, "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	p := path.Join(cfs.root, name)
+	for range maxSymlinks {
+		res, err := cfs.ctr.provider.client.ContainerStatPath(cfs.ctx, cfs.ctr.ID, client.ContainerStatPathOptions{
+			Path: p,
+		})
+		if err != nil {
+			return container.PathStat{}, "", pathError(op, name, err)
+		}
+
+		if res.Stat.Mode&fs.ModeSymlink == 0 {
+			return res.Stat, p, nil
+		}
+
+		target := res.Stat.LinkTarget
+		if !path.IsAbs(target) {
+			target = path.Join(path.Dir(p), target)
+		}
+		p = target
+	}
+
+	return container.PathStat{}, "", &fs.PathError{Op: op, Path: name, Err: errors.New("too many levels of symbolic links")}
+}
+
+// pathError converts err into an [fs.PathError], mapping not found errors to [fs.ErrNotExist].
+func pathError(op string, name string, err error) error {
+	if errdefs.IsNotFound(err) {
+		err = fs.ErrNotExist
+	}
+
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+// readDirEntries returns the direct children of the base directory of the archive, sorted by name.
+func readDirEntries(tr *tar.Reader, base string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("reading tar archive: %w", err)
+		}
+
+		rel, ok := archiveRelPath(base, hdr.Name)
+		if !ok || rel == "." || strings.Contains(rel, "/") {
+			continue
+		}
+
+		entries = append(entries, fs.FileInfoToDirEntry(hdr.FileInfo()))
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return entries, nil
+}
+
+// pathStatInfo implements [fs.FileInfo] for a path stat of the archive API.
+// It reports the same values as the headers of the archives, so the info of
+// a file matches the one of its entry in the parent directory.
+type pathStatInfo struct {
+	stat container.PathStat
+	name string
+}
+
+func (i pathStatInfo) Name() string      { return i.name }
+func (i pathStatInfo) Mode() fs.FileMode { return i.stat.Mode }
+func (i pathStatInfo) IsDir() bool       { return i.stat.Mode.IsDir() }
+func (i pathStatInfo) Sys() any          { return i.stat }
+
+// Size returns the size of regular files, archives don't report the size of directories.
+func (i pathStatInfo) Size() int64 {
+	if i.IsDir() {
+		return 0
+	}
+
+	return i.stat.Size
+}
+
+// ModTime returns the modification time, truncated to seconds as in archives.
+func (i pathStatInfo) ModTime() time.Time {
+	return i.stat.Mtime.Truncate(time.Second).Local()
+}
+
+// containerFile is a regular file of the container filesystem.
+type containerFile struct {
+	info pathStatInfo
+	tr   *tar.Reader
+	rc   io.ReadCloser
+}
+
+// Stat implements [fs.File].
+func (f *containerFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// Read implements [fs.File].
+func (f *containerFile) Read(b []byte) (int, error) {
+	return f.tr.Read(b)
+}
+
+// Close implements [fs.File].
+func (f *containerFile) Close() error {
+	return f.rc.Close()
+}
+
+// containerDir is a directory of the container filesystem.
+type containerDir struct {
+	info    pathStatInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+// Stat implements [fs.File].
+func (d *containerDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+// Read implements [fs.File].
+func (d *containerDir) Read(_ []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// Close implements [fs.File].
+func (d *containerDir) Close() error {
+	return nil
+}
+
+// ReadDir implements [fs.ReadDirFile].
+func (d *containerDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return slices.Clone(remaining), nil
+	}
+
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(remaining))
+	d.offset += n
+
+	return slices.Clone(remaining[:n]), nil
+}
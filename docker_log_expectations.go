@@ -0,0 +1,257 @@
+package testcontainers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultLogMatchTimeout is the default maximum time [DockerContainer.WaitForLog]
+// waits for the logs, matching the default startup timeout of the wait strategies.
+const defaultLogMatchTimeout = 60 * time.Second
+
+// LogMatchOption is an option of [DockerContainer.WaitForLog] and [DockerContainer.ExpectNoLog].
+type LogMatchOption func(*logMatcher)
+
+// MatchRegexp interprets the pattern as a regular expression instead of plain text.
+func MatchRegexp() LogMatchOption {
+	return func(m *logMatcher) {
+		m.isRegexp = true
+	}
+}
+
+// MatchOccurrence sets the number of times the pattern must be found,
+// 1 by default. Non-positive values are ignored.
+func MatchOccurrence(n int) LogMatchOption {
+	return func(m *logMatcher) {
+		if n > 0 {
+			m.occurrence = n
+		}
+	}
+}
+
+// MatchTimeout sets the maximum time [DockerContainer.WaitForLog] waits for
+// the logs, 60 seconds by default. It has no effect on [DockerContainer.ExpectNoLog].
+func MatchTimeout(timeout time.Duration) LogMatchOption {
+	return func(m *logMatcher) {
+		m.timeout = timeout
+	}
+}
+
+// WaitForLog waits until the container logs the pattern, considering only the
+// logs emitted after the call. As with [wait.ForLog], the pattern is plain text
+// unless [MatchRegexp] is used, and is matched against each line of the combined
+// STDOUT and STDERR logs, the number of times set with [MatchOccurrence].
+//
+// The logs are received from the log production of the container, like the log
+// consumers, which is started without the existing logs if it's not running.
+//
+// It returns an error if the pattern is not matched before the timeout, set
+// with [MatchTimeout], or before ctx is done or the container stops.
+func (c *DockerContainer) WaitForLog(ctx context.Context, pattern string, opts ...LogMatchOption) error {
+	m, err := newLogMatcher(pattern, opts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	done, stop, err := c.followNewLogs(m)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	select {
+	case <-m.matched:
+		return nil
+	case <-done:
+		select {
+		case <-m.matched:
+			// Matched by the last logs.
+			return nil
+		default:
+		}
+		return fmt.Errorf("wait for %s: %w", m, errors.Join(m.err(), errors.New("container logs ended")))
+	case <-ctx.Done():
+		return fmt.Errorf("wait for %s: %w", m, errors.Join(m.err(), ctx.Err()))
+	}
+}
+
+// ExpectNoLog checks that the container doesn't log the pattern during the
+// window, considering only the logs emitted after the call. The pattern is
+// matched as in [DockerContainer.WaitForLog], and an error is returned as soon
+// as it's found the number of times set with [MatchOccurrence].
+//
+// The check ends early, without error, if the container stops.
+func (c *DockerContainer) ExpectNoLog(ctx context.Context, pattern string, window time.Duration, opts ...LogMatchOption) error {
+	m, err := newLogMatcher(pattern, opts...)
+	if err != nil {
+		return err
+	}
+
+	done, stop, err := c.followNewLogs(m)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+
+	select {
+	case <-m.matched:
+	case <-done:
+		select {
+		case <-m.matched:
+		default:
+			return nil
+		}
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("expect no %s: %w", m, ctx.Err())
+	}
+
+	return fmt.Errorf("unexpected %s: logged %d times within %s", m, m.matches(), window)
+}
+
+// followNewLogs sends the logs emitted by the container after the call to
+// consumer, until stop is called. The returned channel is closed when the
+// log production ends, e.g. because the container stopped.
+func (c *DockerContainer) followNewLogs(consumer LogConsumer) (<-chan struct{}, func(), error) {
+	done, err := c.ensureLogProduction()
+	if err != nil {
+		return nil, nil, fmt.Errorf("log production: %w", err)
+	}
+
+	return done, c.logWatchers.add(consumer), nil
+}
+
+// logWatchers is a [LogConsumer] dispatching the logs to the consumers
+// watching them for a while, such as the log expectations.
+type logWatchers struct {
+	mtx      sync.Mutex // protects watchers
+	watchers []LogConsumer
+}
+
+// Accept implements [LogConsumer].
+func (w *logWatchers) Accept(l Log) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	for _, consumer := range w.watchers {
+		consumer.Accept(l)
+	}
+}
+
+// AcceptLines implements [LineLogConsumer], as the expectations are matched
+// against complete lines.
+func (w *logWatchers) AcceptLines() bool {
+	return true
+}
+
+// add adds the consumer, returning the function removing it.
+func (w *logWatchers) add(consumer LogConsumer) func() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.watchers = append(w.watchers, consumer)
+
+	return func() {
+		w.mtx.Lock()
+		defer w.mtx.Unlock()
+
+		w.watchers = slices.DeleteFunc(w.watchers, func(other LogConsumer) bool {
+			return other == consumer
+		})
+	}
+}
+
+// logMatcher is a [LogConsumer] counting the occurrences of a pattern
+// in the logs it receives.
+type logMatcher struct {
+	pattern    string
+	isRegexp   bool
+	occurrence int
+	timeout    time.Duration
+	re         *regexp.Regexp
+
+	// matched is closed when the pattern is found the expected number of times.
+	matched chan struct{}
+
+	mtx   sync.Mutex // protects count
+	count int
+}
+
+// newLogMatcher returns a logMatcher for pattern configured with opts.
+func newLogMatcher(pattern string, opts ...LogMatchOption) (*logMatcher, error) {
+	m := &logMatcher{
+		pattern:    pattern,
+		occurrence: 1,
+		timeout:    defaultLogMatchTimeout,
+		matched:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.isRegexp {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile pattern: %w", err)
+		}
+		m.re = re
+	}
+
+	return m, nil
+}
+
+// Accept implements [LogConsumer], adding the occurrences of the pattern
+// in the log line to the count.
+func (m *logMatcher) Accept(l Log) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.count >= m.occurrence {
+		return
+	}
+
+	if m.re != nil {
+		m.count += len(m.re.FindAllIndex(l.Content, -1))
+	} else {
+		m.count += bytes.Count(l.Content, []byte(m.pattern))
+	}
+
+	if m.count >= m.occurrence {
+		close(m.matched)
+	}
+}
+
+// matches returns the number of occurrences of the pattern found so far.
+func (m *logMatcher) matches() int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.count
+}
+
+// err returns the error describing the occurrences found so far.
+func (m *logMatcher) err() error {
+	return fmt.Errorf("matched %d times, expected %d", m.matches(), m.occurrence)
+}
+
+// String returns a human-readable description of the pattern.
+func (m *logMatcher) String() string {
+	if m.isRegexp {
+		return fmt.Sprintf("log pattern `%s`", m.pattern)
+	}
+
+	return fmt.Sprintf("log message %q", m.pattern)
+}
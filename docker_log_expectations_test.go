@@ -0,0 +1,124 @@
+package testcontainers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestLogMatcher(t *testing.T) {
+	isMatched := func(m *logMatcher) bool {
+		select {
+		case <-m.matched:
+			return true
+		default:
+			return false
+		}
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		m, err := newLogMatcher("ready.")
+		require.NoError(t, err)
+
+		m.Accept(Log{Content: []byte("readyX\n")})
+		require.False(t, isMatched(m))
+
+		m.Accept(Log{Content: []byte("ready.\n")})
+		require.True(t, isMatched(m))
+		require.Equal(t, 1, m.matches())
+	})
+
+	t.Run("per-line", func(t *testing.T) {
+		m, err := newLogMatcher("ready", MatchOccurrence(3))
+		require.NoError(t, err)
+
+		// The occurrences are counted line by line, and add up.
+		m.Accept(Log{Content: []byte("ready ready\n")})
+		require.Equal(t, 2, m.matches())
+		require.False(t, isMatched(m))
+
+		m.Accept(Log{Content: []byte("not yet\n")})
+		m.Accept(Log{Content: []byte("ready\n")})
+		require.True(t, isMatched(m))
+	})
+
+	t.Run("regexp/occurrence", func(t *testing.T) {
+		m, err := newLogMatcher(`request \d+ done`, MatchRegexp(), MatchOccurrence(2))
+		require.NoError(t, err)
+
+		m.Accept(Log{Content: []byte("request 1 done\n")})
+		require.False(t, isMatched(m))
+		require.EqualError(t, m.err(), "matched 1 times, expected 2")
+
+		m.Accept(Log{Content: []byte("request 2 done\n")})
+		require.True(t, isMatched(m))
+
+		// Logs received after the match are ignored.
+		m.Accept(Log{Content: []byte("request 3 done\n")})
+		require.Equal(t, 2, m.matches())
+	})
+
+	t.Run("invalid-regexp", func(t *testing.T) {
+		_, err := newLogMatcher(`(`, MatchRegexp())
+		require.Error(t, err)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		m, err := newLogMatcher("ready")
+		require.NoError(t, err)
+		require.Equal(t, `log message "ready"`, m.String())
+
+		m, err = newLogMatcher(`re.dy`, MatchRegexp())
+		require.NoError(t, err)
+		require.Equal(t, "log pattern `re.dy`", m.String())
+	})
+}
+
+func TestDockerContainerWaitForLog(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := Run(ctx, alpineImage,
+		WithCmd("sh", "-c", "echo 'started'; echo 'started' >&2; sleep 60"),
+		WithWaitStrategy(wait.ForLog("started").WithOccurrence(2)),
+	)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	// emit writes to the logs of the container.
+	emit := func(cmd string) {
+		time.Sleep(500 * time.Millisecond)
+		_, _, err := ctr.Exec(ctx, []string{"sh", "-c", cmd + " > /proc/1/fd/1"})
+		assert.NoError(t, err)
+	}
+
+	t.Run("only-new-logs", func(t *testing.T) {
+		err := ctr.WaitForLog(ctx, "started", MatchTimeout(2*time.Second))
+		require.ErrorContains(t, err, `wait for log message "started": matched 0 times, expected 1`)
+	})
+
+	t.Run("match", func(t *testing.T) {
+		go emit("echo 'request 1 done'; echo 'request 2 done'")
+
+		err := ctr.WaitForLog(ctx, `request \d done`, MatchRegexp(), MatchOccurrence(2), MatchTimeout(10*time.Second))
+		require.NoError(t, err)
+	})
+
+	t.Run("expect-no-log", func(t *testing.T) {
+		go emit("echo 'all good'")
+
+		err := ctr.ExpectNoLog(ctx, "panic", 2*time.Second)
+		require.NoError(t, err)
+	})
+
+	t.Run("expect-no-log/unexpected", func(t *testing.T) {
+		go emit("echo 'panic: oops'")
+
+		err := ctr.ExpectNoLog(ctx, "panic", 10*time.Second)
+		require.ErrorContains(t, err, `unexpected log message "panic": logged 1 times within 10s`)
+	})
+}
@@ -0,0 +1,13 @@
+package testcontainers
+
+import (
+	"context"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Metrics scrapes the metrics in the Prometheus text exposition format served
+// by the container at path on port, e.g. "9090/tcp" and "/metrics".
+func (c *DockerContainer) Metrics(ctx context.Context, port string, path string) (wait.Metrics, error) {
+	return wait.ScrapeMetrics(ctx, c, port, path)
+}
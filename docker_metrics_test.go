@@ -0,0 +1,60 @@
+package testcontainers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestDockerContainerMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	const metrics = `# TYPE cache_warm gauge
+cache_warm 1
+queue_size{queue="orders"} 3
+`
+
+	ctr, err := Run(ctx, alpineImage,
+		WithFiles(ContainerFile{
+			Reader:            strings.NewReader(metrics),
+			ContainerFilePath: "/www/metrics",
+			FileMode:          0o644,
+		}),
+		WithCmd("httpd", "-f", "-p", "8080", "-h", "/www"),
+		WithExposedPorts("8080/tcp"),
+		WithWaitStrategy(
+			wait.ForMetric("8080/tcp", "/metrics").
+				WithName("cache_warm").
+				WithMatcher(func(v float64) bool { return v == 1 }).
+				WithStartupTimeout(30*time.Second),
+		),
+	)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	scraped, err := ctr.Metrics(ctx, "8080/tcp", "/metrics")
+	require.NoError(t, err)
+
+	v, ok := scraped.Value("queue_size", map[string]string{"queue": "orders"})
+	require.True(t, ok)
+	require.Equal(t, 3.0, v)
+
+	require.True(t, AssertMetric(ctx, t, ctr, MetricAssertion{
+		Port:  "8080/tcp",
+		Name:  "queue_size",
+		Match: func(v float64) bool { return v < 10 },
+	}))
+
+	// a nil matcher only checks that the metric exists.
+	require.True(t, AssertMetric(ctx, t, ctr, MetricAssertion{
+		Port:   "8080/tcp",
+		Path:   "/metrics",
+		Name:   "queue_size",
+		Labels: map[string]string{"queue": "orders"},
+	}))
+}
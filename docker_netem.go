@@ -0,0 +1,165 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NetworkConditions are the network conditions emulated on the network
+// interfaces of a container with tc netem, affecting all its traffic.
+// The zero value emulates no condition.
+type NetworkConditions struct {
+	// Latency is the delay added to the outgoing packets.
+	Latency time.Duration
+
+	// Jitter is the random variation of the latency.
+	Jitter time.Duration
+
+	// Loss is the percentage of outgoing packets dropped, between 0 and 100.
+	Loss float64
+
+	// Rate is the bandwidth limit of the outgoing traffic, in bits per second, unlimited if zero.
+	Rate uint64
+}
+
+// IsZero reports whether the conditions emulate nothing.
+func (nc NetworkConditions) IsZero() bool {
+	return nc == (NetworkConditions{})
+}
+
+// validate checks the conditions are valid.
+func (nc NetworkConditions) validate() error {
+	switch {
+	case nc.Latency < 0:
+		return fmt.Errorf("negative latency %s", nc.Latency)
+	case nc.Jitter < 0:
+		return fmt.Errorf("negative jitter %s", nc.Jitter)
+	case nc.Jitter > 0 && nc.Latency == 0:
+		return errors.New("jitter without latency")
+	case nc.Loss < 0 || nc.Loss > 100:
+		return fmt.Errorf("loss %v%% out of range [0, 100]", nc.Loss)
+	}
+
+	return nil
+}
+
+// netemArgs returns the arguments of the tc netem qdisc emulating the conditions.
+func (nc NetworkConditions) netemArgs() string {
+	args := []string{"netem"}
+	if nc.Latency > 0 {
+		args = append(args, "delay", formatNetemTime(nc.Latency))
+		if nc.Jitter > 0 {
+			args = append(args, formatNetemTime(nc.Jitter))
+		}
+	}
+
+	if nc.Loss > 0 {
+		args = append(args, "loss", strconv.FormatFloat(nc.Loss, 'f', -1, 64)+"%")
+	}
+
+	if nc.Rate > 0 {
+		args = append(args, "rate", strconv.FormatUint(nc.Rate, 10)+"bit")
+	}
+
+	return strings.Join(args, " ")
+}
+
+// formatNetemTime formats the duration in microseconds, the unit with the
+// finest resolution supported by tc.
+func formatNetemTime(d time.Duration) string {
+	return strconv.FormatInt(d.Microseconds(), 10) + "us"
+}
+
+// netemCommand returns the shell command which applies the conditions to all
+// the network interfaces but the loopback one, or removes them if the
+// conditions are zero.
+func netemCommand(nc NetworkConditions) string {
+	qdisc := "tc qdisc replace dev $dev root " + nc.netemArgs()
+	if nc.IsZero() {
+		// Removing a qdisc which doesn't exist fails, so ignore the errors.
+		qdisc = "tc qdisc del dev $dev root 2>/dev/null || true"
+	}
+
+	return `set -e
+for dev in $(ls /sys/class/net); do
+  [ "$dev" = lo ] && continue
+  ` + qdisc + `
+done`
+}
+
+// WithNetworkConditions emulates the network conditions on the container once
+// it's started, e.g. latency or packet loss, affecting every protocol and
+// every peer, including the other containers. See [DockerContainer.SetNetworkConditions].
+func WithNetworkConditions(netem NetworkConditions) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if err := netem.validate(); err != nil {
+			return fmt.Errorf("network conditions: %w", err)
+		}
+
+		if netem.IsZero() {
+			return nil
+		}
+
+		return WithAdditionalLifecycleHooks(ContainerLifecycleHooks{
+			PostStarts: []ContainerHook{
+				func(ctx context.Context, c Container) error {
+					dockerContainer, ok := c.(*DockerContainer)
+					if !ok {
+						return fmt.Errorf("network conditions: unsupported container type %T", c)
+					}
+
+					return dockerContainer.applyNetworkConditions(ctx, netem)
+				},
+			},
+		})(req)
+	}
+}
+
+// SetNetworkConditions emulates the network conditions on the running container:
+// the latency and its jitter, the percentage of packet loss, and the bandwidth
+// limit in bits per second, which apply to the outgoing traffic of every network
+// interface. Zero values emulate nothing, so all zeros restores the network.
+//
+// The conditions are set with tc netem in a privileged sidecar container sharing
+// the network namespace of the container, so they affect every protocol and
+// every peer. They're lost if the container is restarted.
+func (c *DockerContainer) SetNetworkConditions(ctx context.Context, latency time.Duration, jitter time.Duration, loss float64, rate uint64) error {
+	netem := NetworkConditions{
+		Latency: latency,
+		Jitter:  jitter,
+		Loss:    loss,
+		Rate:    rate,
+	}
+
+	if err := netem.validate(); err != nil {
+		return fmt.Errorf("network conditions: %w", err)
+	}
+
+	return c.applyNetworkConditions(ctx, netem)
+}
+
+// applyNetworkConditions applies the conditions from a sidecar container,
+// which is removed once done, as the conditions persist in the network namespace.
+func (c *DockerContainer) applyNetworkConditions(ctx context.Context, netem NetworkConditions) (err error) {
+	sidecar, err := newNetworkSidecar(ctx, c.ID)
+	if sidecar != nil {
+		defer func() {
+			if errTerminate := TerminateContainer(sidecar.DockerContainer); errTerminate != nil {
+				err = errors.Join(err, fmt.Errorf("terminate network sidecar: %w", errTerminate))
+			}
+		}()
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = sidecar.run(ctx, netemCommand(netem)); err != nil {
+		return fmt.Errorf("set network conditions of container %.12s: %w", c.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package testcontainers
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+func TestNetworkConditions_netemArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		netem    NetworkConditions
+		expected string
+	}{
+		{
+			name:     "latency",
+			netem:    NetworkConditions{Latency: 100 * time.Millisecond},
+			expected: "netem delay 100000us",
+		},
+		{
+			name:     "jitter",
+			netem:    NetworkConditions{Latency: 100 * time.Millisecond, Jitter: 1500 * time.Microsecond},
+			expected: "netem delay 100000us 1500us",
+		},
+		{
+			name:     "loss",
+			netem:    NetworkConditions{Loss: 2.5},
+			expected: "netem loss 2.5%",
+		},
+		{
+			name:     "rate",
+			netem:    NetworkConditions{Rate: 1_000_000},
+			expected: "netem rate 1000000bit",
+		},
+		{
+			name:     "all",
+			netem:    NetworkConditions{Latency: time.Second, Jitter: time.Millisecond, Loss: 10, Rate: 8000},
+			expected: "netem delay 1000000us 1000us loss 10% rate 8000bit",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.netem.validate())
+			require.Equal(t, tc.expected, tc.netem.netemArgs())
+			require.False(t, tc.netem.IsZero())
+		})
+	}
+}
+
+func TestNetworkConditions_validate(t *testing.T) {
+	require.NoError(t, NetworkConditions{}.validate())
+	require.True(t, NetworkConditions{}.IsZero())
+
+	require.EqualError(t, NetworkConditions{Latency: -time.Second}.validate(), "negative latency -1s")
+	require.EqualError(t, NetworkConditions{Latency: time.Second, Jitter: -time.Second}.validate(), "negative jitter -1s")
+	require.EqualError(t, NetworkConditions{Jitter: time.Second}.validate(), "jitter without latency")
+	require.EqualError(t, NetworkConditions{Loss: 101}.validate(), "loss 101% out of range [0, 100]")
+	require.EqualError(t, NetworkConditions{Loss: -1}.validate(), "loss -1% out of range [0, 100]")
+}
+
+func TestNetemCommand(t *testing.T) {
+	require.Equal(t, `set -e
+for dev in $(ls /sys/class/net); do
+  [ "$dev" = lo ] && continue
+  tc qdisc replace dev $dev root netem delay 50000us
+done`, netemCommand(NetworkConditions{Latency: 50 * time.Millisecond}))
+
+	require.Equal(t, `set -e
+for dev in $(ls /sys/class/net); do
+  [ "$dev" = lo ] && continue
+  tc qdisc del dev $dev root 2>/dev/null || true
+done`, netemCommand(NetworkConditions{}))
+}
+
+func TestWithNetworkConditions_invalid(t *testing.T) {
+	req := &GenericContainerRequest{}
+	err := WithNetworkConditions(NetworkConditions{Loss: 200}).Customize(req)
+	require.EqualError(t, err, "network conditions: loss 200% out of range [0, 100]")
+	require.Empty(t, req.LifecycleHooks)
+
+	require.NoError(t, WithNetworkConditions(NetworkConditions{}).Customize(req))
+	require.Empty(t, req.LifecycleHooks)
+}
+
+// qdisc returns the queueing discipline of the eth0 interface of the container.
+func qdisc(ctx context.Context, t *testing.T, ctr *DockerContainer) string {
+	t.Helper()
+
+	code, reader, err := ctr.Exec(ctx, []string{"tc", "qdisc", "show", "dev", "eth0"}, tcexec.Multiplexed())
+	require.NoError(t, err)
+
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Zero(t, code, string(out))
+
+	return string(out)
+}
+
+func TestDockerContainer_SetNetworkConditions(t *testing.T) {
+	ctx := context.Background()
+
+	// The tools image is used as the target too, to inspect the qdisc.
+	ctr, err := Run(ctx, networkToolsImage,
+		WithEntrypoint("tail", "-f", "/dev/null"),
+		WithNetworkConditions(NetworkConditions{Latency: 100 * time.Millisecond}),
+	)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	require.Contains(t, qdisc(ctx, t, ctr), "netem")
+	require.Contains(t, qdisc(ctx, t, ctr), "delay 100ms")
+
+	require.NoError(t, ctr.SetNetworkConditions(ctx, 200*time.Millisecond, 10*time.Millisecond, 5, 1_000_000))
+	got := qdisc(ctx, t, ctr)
+	require.Contains(t, got, "delay 200ms  10ms")
+	require.Contains(t, got, "loss 5%")
+	require.Contains(t, got, "rate 1Mbit")
+
+	require.NoError(t, ctr.SetNetworkConditions(ctx, 0, 0, 0, 0))
+	require.NotContains(t, qdisc(ctx, t, ctr), "netem")
+
+	// Restoring the network again is a no-op.
+	require.NoError(t, ctr.SetNetworkConditions(ctx, 0, 0, 0, 0))
+
+	require.Error(t, ctr.SetNetworkConditions(ctx, 0, time.Second, 0, 0))
+}
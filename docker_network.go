@@ -0,0 +1,361 @@
+package testcontainers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/client"
+
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+const (
+	// hubNetworkToolsImage {
+	networkToolsImage string = "nicolaka/netshoot:v0.13"
+	// }
+
+	// partitionChainPrefix is the prefix of the iptables chains of the partitions.
+	partitionChainPrefix = "TC-PARTITION-"
+)
+
+// dockerProvider returns the provider of the network, or a new one if the
+// network wasn't created by a provider, e.g. when it's looked up.
+func (n *DockerNetwork) dockerProvider() (*DockerProvider, error) {
+	n.providerMtx.Lock()
+	defer n.providerMtx.Unlock()
+
+	if n.provider != nil {
+		return n.provider, nil
+	}
+
+	provider, err := NewDockerProvider()
+	if err != nil {
+		return nil, fmt.Errorf("new docker provider: %w", err)
+	}
+
+	n.provider = provider
+
+	return provider, nil
+}
+
+// Connect connects the running container to the network, reachable by the
+// other containers of the network with the given aliases.
+func (n *DockerNetwork) Connect(ctx context.Context, ctr Container, aliases ...string) error {
+	provider, err := n.dockerProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+
+	_, err = provider.client.NetworkConnect(ctx, n.ID, client.NetworkConnectOptions{
+		Container: ctr.GetContainerID(),
+		EndpointConfig: &network.EndpointSettings{
+			Aliases: aliases,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("network connect %s: %w", n.Name, err)
+	}
+
+	return nil
+}
+
+// Disconnect disconnects the container from the network.
+func (n *DockerNetwork) Disconnect(ctx context.Context, ctr Container) error {
+	provider, err := n.dockerProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+
+	_, err = provider.client.NetworkDisconnect(ctx, n.ID, client.NetworkDisconnectOptions{
+		Container: ctr.GetContainerID(),
+	})
+	if err != nil {
+		return fmt.Errorf("network disconnect %s: %w", n.Name, err)
+	}
+
+	return nil
+}
+
+// NetworkPartition is a partition of the containers of a network, created with
+// [DockerNetwork.Partition]. The containers of a group can't reach the
+// containers of the other groups until the partition is healed.
+type NetworkPartition struct {
+	chain    string
+	mtx      sync.Mutex
+	sidecars []*partitionSidecar
+}
+
+// partitionSidecar is the sidecar of a partitioned container, which manages
+// its iptables rules.
+type partitionSidecar struct {
+	*networkSidecar
+
+	// heal is the command removing the rules of the partition, empty if
+	// the sidecar failed to start.
+	heal string
+}
+
+// Partition isolates the groups of containers of the network from each other,
+// dropping the traffic between the addresses of the containers of different
+// groups, while the containers of a group can still reach each other, as well
+// as the containers not part of any group.
+//
+// The rules are set with iptables in a privileged sidecar container sharing the
+// network namespace of each container, so the partition is limited to the
+// network. Use [NetworkPartition.Heal] to restore the connectivity, or
+// [CleanupPartition] in tests.
+func (n *DockerNetwork) Partition(ctx context.Context, groups ...[]Container) (*NetworkPartition, error) {
+	if len(groups) < 2 {
+		return nil, errors.New("partition needs at least two groups")
+	}
+
+	// Resolve the addresses of the containers on the network.
+	addrs := make([][]netip.Addr, len(groups))
+	seen := make(map[string]bool)
+	for i, group := range groups {
+		for _, ctr := range group {
+			id := ctr.GetContainerID()
+			if seen[id] {
+				return nil, fmt.Errorf("container %.12s is in more than one group", id)
+			}
+			seen[id] = true
+
+			ips, err := n.containerAddrs(ctx, ctr)
+			if err != nil {
+				return nil, err
+			}
+			addrs[i] = append(addrs[i], ips...)
+		}
+	}
+
+	p := &NetworkPartition{
+		chain: partitionChainPrefix + uuid.NewString()[:8],
+	}
+
+	for i, group := range groups {
+		var blocked []netip.Addr
+		for j, other := range addrs {
+			if i != j {
+				blocked = append(blocked, other...)
+			}
+		}
+		if len(blocked) == 0 {
+			continue
+		}
+
+		apply, heal := partitionRules(p.chain, blocked)
+		for _, ctr := range group {
+			if err := p.apply(ctx, ctr.GetContainerID(), apply, heal); err != nil {
+				return nil, errors.Join(err, p.Heal(ctx))
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// containerAddrs returns the IPv4 and IPv6 addresses of the container on the network.
+func (n *DockerNetwork) containerAddrs(ctx context.Context, ctr Container) ([]netip.Addr, error) {
+	inspect, err := ctr.Inspect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("inspect container %.12s: %w", ctr.GetContainerID(), err)
+	}
+
+	var settings *network.EndpointSettings
+	if inspect.NetworkSettings != nil {
+		for name, s := range inspect.NetworkSettings.Networks {
+			if s != nil && (name == n.Name || s.NetworkID == n.ID) {
+				settings = s
+				break
+			}
+		}
+	}
+
+	if settings == nil {
+		return nil, fmt.Errorf("container %.12s is not connected to network %s", ctr.GetContainerID(), n.Name)
+	}
+
+	var addrs []netip.Addr
+	for _, addr := range []netip.Addr{settings.IPAddress, settings.GlobalIPv6Address} {
+		if addr.IsValid() {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("container %.12s has no address on network %s", ctr.GetContainerID(), n.Name)
+	}
+
+	return addrs, nil
+}
+
+// apply starts the sidecar of the container, running the apply command.
+func (p *NetworkPartition) apply(ctx context.Context, target string, apply string, heal string) error {
+	ns, err := newNetworkSidecar(ctx, target)
+	if ns == nil {
+		return err
+	}
+
+	sidecar := &partitionSidecar{networkSidecar: ns}
+	if err == nil {
+		// Record the heal command before applying the rules, so that Heal
+		// removes them even if applying them fails halfway.
+		sidecar.heal = heal
+	}
+
+	p.mtx.Lock()
+	p.sidecars = append(p.sidecars, sidecar)
+	p.mtx.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if err = sidecar.run(ctx, apply); err != nil {
+		return fmt.Errorf("partition container %.12s: %w", target, err)
+	}
+
+	return nil
+}
+
+// Heal restores the connectivity between the groups of the partition, removing
+// the iptables rules and the sidecar containers. It's safe to call it more than once.
+func (p *NetworkPartition) Heal(ctx context.Context) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	var errs []error
+	for _, sidecar := range p.sidecars {
+		if sidecar.heal != "" {
+			if err := sidecar.run(ctx, sidecar.heal); err != nil {
+				errs = append(errs, fmt.Errorf("heal container %.12s: %w", sidecar.target, err))
+			}
+		}
+
+		if err := TerminateContainer(sidecar.DockerContainer); err != nil {
+			errs = append(errs, fmt.Errorf("terminate network sidecar: %w", err))
+		}
+	}
+
+	p.sidecars = nil
+
+	return errors.Join(errs...)
+}
+
+// networkSidecar is a privileged container sharing the network namespace of
+// a container, which runs the network tools changing its network configuration.
+type networkSidecar struct {
+	*DockerContainer
+
+	// target is the ID of the container.
+	target string
+}
+
+// newNetworkSidecar runs a privileged sidecar container sharing the network
+// namespace of the target container.
+func newNetworkSidecar(ctx context.Context, target string) (*networkSidecar, error) {
+	c, err := Run(ctx, networkToolsImage,
+		WithEntrypoint("tail", "-f", "/dev/null"),
+		WithHostConfigModifier(func(hostConfig *container.HostConfig) {
+			hostConfig.NetworkMode = container.NetworkMode("container:" + target)
+			hostConfig.Privileged = true
+		}),
+	)
+	var sidecar *networkSidecar
+	if c != nil {
+		sidecar = &networkSidecar{DockerContainer: c, target: target}
+	}
+
+	if err != nil {
+		return sidecar, fmt.Errorf("run network sidecar for container %.12s: %w", target, err)
+	}
+
+	return sidecar, nil
+}
+
+// run runs the shell command in the sidecar, returning its output on failure.
+func (s *networkSidecar) run(ctx context.Context, command string) error {
+	code, reader, err := s.Exec(ctx, []string{"sh", "-c", command}, tcexec.Multiplexed())
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	if code != 0 {
+		output, _ := io.ReadAll(reader)
+		return fmt.Errorf("exit code %d: %s", code, bytes.TrimSpace(output))
+	}
+
+	return nil
+}
+
+// partitionRules returns the shell commands which apply and remove the rules
+// dropping the traffic from and to the blocked addresses, in a dedicated chain.
+//
+// Both commands run all their steps, failing if any did. The heal command
+// tolerates the failure of the steps removing rules which were never applied,
+// failing only if the chain still exists afterwards.
+func partitionRules(chain string, blocked []netip.Addr) (string, string) {
+	var apply, heal, check []string
+	for _, cmd := range []string{"iptables", "ip6tables"} {
+		var rules []string
+		for _, addr := range blocked {
+			if addr.Is4() != (cmd == "iptables") {
+				continue
+			}
+			rules = append(rules,
+				fmt.Sprintf("%s -A %s -s %s -j DROP", cmd, chain, addr),
+				fmt.Sprintf("%s -A %s -d %s -j DROP", cmd, chain, addr),
+			)
+		}
+
+		if len(rules) == 0 {
+			continue
+		}
+
+		apply = append(apply, fmt.Sprintf("%s -N %s", cmd, chain))
+		apply = append(apply, rules...)
+		apply = append(apply,
+			fmt.Sprintf("%s -I INPUT -j %s", cmd, chain),
+			fmt.Sprintf("%s -I OUTPUT -j %s", cmd, chain),
+		)
+
+		heal = append(heal,
+			fmt.Sprintf("%s -D INPUT -j %s", cmd, chain),
+			fmt.Sprintf("%s -D OUTPUT -j %s", cmd, chain),
+			fmt.Sprintf("%s -F %s", cmd, chain),
+			fmt.Sprintf("%s -X %s", cmd, chain),
+		)
+
+		check = append(check, fmt.Sprintf(
+			"if %[1]s -n -L %[2]s >/dev/null 2>&1; then echo \"%[1]s chain %[2]s still exists\" >&2; rc=1; fi",
+			cmd, chain,
+		))
+	}
+
+	for i, step := range apply {
+		apply[i] = step + " || rc=1"
+	}
+
+	for i, step := range heal {
+		heal[i] = step + " || true"
+	}
+
+	return shellScript(apply), shellScript(append(heal, check...))
+}
+
+// shellScript returns a shell script running all the steps, exiting with the
+// status rc the steps set on failure.
+func shellScript(steps []string) string {
+	return "rc=0\n" + strings.Join(steps, "\n") + "\nexit $rc"
+}
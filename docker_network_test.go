@@ -0,0 +1,161 @@
+package testcontainers_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/network"
+)
+
+// canPing reports whether the container can ping the host.
+func canPing(ctx context.Context, t *testing.T, ctr testcontainers.Container, host string) bool {
+	t.Helper()
+
+	code, _, err := ctr.Exec(ctx, []string{"ping", "-c", "1", "-W", "1", host}, tcexec.Multiplexed())
+	require.NoError(t, err)
+
+	return code == 0
+}
+
+// runPingable runs an alpine container on the network with the alias.
+func runPingable(ctx context.Context, t *testing.T, nw *testcontainers.DockerNetwork, alias string) *testcontainers.DockerContainer {
+	t.Helper()
+
+	ctr, err := testcontainers.Run(ctx, "alpine:3.20",
+		testcontainers.WithEntrypoint("tail", "-f", "/dev/null"),
+		network.WithNetwork([]string{alias}, nw),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	return ctr
+}
+
+func TestDockerNetwork_ConnectDisconnect(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, nw)
+
+	other, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, other)
+
+	a := runPingable(ctx, t, nw, "a")
+	b := runPingable(ctx, t, other, "b")
+
+	require.False(t, canPing(ctx, t, a, "b2"))
+
+	require.NoError(t, nw.Connect(ctx, b, "b2"))
+	require.True(t, canPing(ctx, t, a, "b2"))
+
+	networks, err := b.Networks(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{nw.Name, other.Name}, networks)
+
+	require.NoError(t, nw.Disconnect(ctx, b))
+	require.False(t, canPing(ctx, t, a, "b2"))
+
+	networks, err = b.Networks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{other.Name}, networks)
+
+	require.Error(t, nw.Disconnect(ctx, b))
+}
+
+func TestDockerNetwork_ConnectConcurrently(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, nw)
+
+	a := runPingable(ctx, t, nw, "a")
+
+	// A network not created by a provider, as the compose module does,
+	// creates its provider on first use.
+	lookedUp := &testcontainers.DockerNetwork{ID: nw.ID, Name: nw.Name}
+
+	ctrs := make([]*testcontainers.DockerContainer, 3)
+	for i := range ctrs {
+		ctr, err := testcontainers.Run(ctx, "alpine:3.20", testcontainers.WithEntrypoint("tail", "-f", "/dev/null"))
+		testcontainers.CleanupContainer(t, ctr)
+		require.NoError(t, err)
+		ctrs[i] = ctr
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(ctrs))
+	for i, ctr := range ctrs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = lookedUp.Connect(ctx, ctr, fmt.Sprintf("c%d", i))
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		require.NoError(t, err)
+		require.True(t, canPing(ctx, t, a, fmt.Sprintf("c%d", i)))
+	}
+}
+
+func TestDockerNetwork_Partition(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, nw)
+
+	a := runPingable(ctx, t, nw, "a")
+	b := runPingable(ctx, t, nw, "b")
+	c := runPingable(ctx, t, nw, "c")
+
+	partition, err := nw.Partition(ctx, []testcontainers.Container{a, b}, []testcontainers.Container{c})
+	testcontainers.CleanupPartition(t, partition)
+	require.NoError(t, err)
+
+	require.True(t, canPing(ctx, t, a, "b"))
+	require.False(t, canPing(ctx, t, a, "c"))
+	require.False(t, canPing(ctx, t, c, "a"))
+	require.False(t, canPing(ctx, t, c, "b"))
+
+	require.NoError(t, partition.Heal(ctx))
+	require.True(t, canPing(ctx, t, a, "c"))
+	require.True(t, canPing(ctx, t, c, "b"))
+
+	// Healing again is a no-op.
+	require.NoError(t, partition.Heal(ctx))
+}
+
+func TestDockerNetwork_PartitionErrors(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, nw)
+
+	other, err := network.New(ctx)
+	require.NoError(t, err)
+	testcontainers.CleanupNetwork(t, other)
+
+	a := runPingable(ctx, t, nw, "a")
+	b := runPingable(ctx, t, other, "b")
+
+	_, err = nw.Partition(ctx, []testcontainers.Container{a})
+	require.EqualError(t, err, "partition needs at least two groups")
+
+	_, err = nw.Partition(ctx, []testcontainers.Container{a}, []testcontainers.Container{a})
+	require.ErrorContains(t, err, "is in more than one group")
+
+	_, err = nw.Partition(ctx, []testcontainers.Container{a}, []testcontainers.Container{b})
+	require.ErrorContains(t, err, "is not connected to network "+nw.Name)
+}
@@ -0,0 +1,68 @@
+package testcontainers
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionRules(t *testing.T) {
+	t.Run("ipv4", func(t *testing.T) {
+		apply, heal := partitionRules("TC-PARTITION-1", []netip.Addr{
+			netip.MustParseAddr("172.18.0.3"),
+			netip.MustParseAddr("172.18.0.4"),
+		})
+
+		require.Equal(t, `rc=0
+iptables -N TC-PARTITION-1 || rc=1
+iptables -A TC-PARTITION-1 -s 172.18.0.3 -j DROP || rc=1
+iptables -A TC-PARTITION-1 -d 172.18.0.3 -j DROP || rc=1
+iptables -A TC-PARTITION-1 -s 172.18.0.4 -j DROP || rc=1
+iptables -A TC-PARTITION-1 -d 172.18.0.4 -j DROP || rc=1
+iptables -I INPUT -j TC-PARTITION-1 || rc=1
+iptables -I OUTPUT -j TC-PARTITION-1 || rc=1
+exit $rc`, apply)
+
+		require.Equal(t, `rc=0
+iptables -D INPUT -j TC-PARTITION-1 || true
+iptables -D OUTPUT -j TC-PARTITION-1 || true
+iptables -F TC-PARTITION-1 || true
+iptables -X TC-PARTITION-1 || true
+if iptables -n -L TC-PARTITION-1 >/dev/null 2>&1; then echo "iptables chain TC-PARTITION-1 still exists" >&2; rc=1; fi
+exit $rc`, heal)
+	})
+
+	t.Run("dual-stack", func(t *testing.T) {
+		apply, heal := partitionRules("TC-PARTITION-2", []netip.Addr{
+			netip.MustParseAddr("172.18.0.3"),
+			netip.MustParseAddr("fd00::3"),
+		})
+
+		require.Equal(t, `rc=0
+iptables -N TC-PARTITION-2 || rc=1
+iptables -A TC-PARTITION-2 -s 172.18.0.3 -j DROP || rc=1
+iptables -A TC-PARTITION-2 -d 172.18.0.3 -j DROP || rc=1
+iptables -I INPUT -j TC-PARTITION-2 || rc=1
+iptables -I OUTPUT -j TC-PARTITION-2 || rc=1
+ip6tables -N TC-PARTITION-2 || rc=1
+ip6tables -A TC-PARTITION-2 -s fd00::3 -j DROP || rc=1
+ip6tables -A TC-PARTITION-2 -d fd00::3 -j DROP || rc=1
+ip6tables -I INPUT -j TC-PARTITION-2 || rc=1
+ip6tables -I OUTPUT -j TC-PARTITION-2 || rc=1
+exit $rc`, apply)
+
+		require.Equal(t, `rc=0
+iptables -D INPUT -j TC-PARTITION-2 || true
+iptables -D OUTPUT -j TC-PARTITION-2 || true
+iptables -F TC-PARTITION-2 || true
+iptables -X TC-PARTITION-2 || true
+ip6tables -D INPUT -j TC-PARTITION-2 || true
+ip6tables -D OUTPUT -j TC-PARTITION-2 || true
+ip6tables -F TC-PARTITION-2 || true
+ip6tables -X TC-PARTITION-2 || true
+if iptables -n -L TC-PARTITION-2 >/dev/null 2>&1; then echo "iptables chain TC-PARTITION-2 still exists" >&2; rc=1; fi
+if ip6tables -n -L TC-PARTITION-2 >/dev/null 2>&1; then echo "ip6tables chain TC-PARTITION-2 still exists" >&2; rc=1; fi
+exit $rc`, heal)
+	})
+}
@@ -0,0 +1,326 @@
+package testcontainers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// StatsSample is a sample of the resource usage of a container.
+type StatsSample struct {
+	// Time is the time the sample was read by the daemon.
+	Time time.Time `json:"time"`
+
+	// CPUPercent is the CPU usage since the previous sample, as computed by docker stats,
+	// where 100% is a whole CPU. It's zero for the first sample of a stream.
+	CPUPercent float64 `json:"cpu_percent"`
+
+	// MemoryUsage is the memory used by the container in bytes, excluding the
+	// inactive page cache, as reported by docker stats.
+	MemoryUsage uint64 `json:"memory_usage"`
+
+	// MemoryLimit is the memory limit of the container in bytes.
+	MemoryLimit uint64 `json:"memory_limit"`
+
+	// NetworkRx is the number of bytes received by the container on all its networks.
+	NetworkRx uint64 `json:"network_rx"`
+
+	// NetworkTx is the number of bytes sent by the container on all its networks.
+	NetworkTx uint64 `json:"network_tx"`
+
+	// BlockRead is the number of bytes read by the container from block devices.
+	BlockRead uint64 `json:"block_read"`
+
+	// BlockWrite is the number of bytes written by the container to block devices.
+	BlockWrite uint64 `json:"block_write"`
+
+	// PIDs is the number of processes or threads of the container.
+	PIDs uint64 `json:"pids"`
+}
+
+// newStatsSample converts the stats of the Docker API into a sample.
+func newStatsSample(s container.StatsResponse) StatsSample {
+	sample := StatsSample{
+		Time:        s.Read,
+		CPUPercent:  cpuPercent(s.CPUStats, s.PreCPUStats),
+		MemoryUsage: memoryUsage(s.MemoryStats),
+		MemoryLimit: s.MemoryStats.Limit,
+		PIDs:        s.PidsStats.Current,
+	}
+
+	for _, n := range s.Networks {
+		sample.NetworkRx += n.RxBytes
+		sample.NetworkTx += n.TxBytes
+	}
+
+	for _, e := range s.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			sample.BlockRead += e.Value
+		case "write":
+			sample.BlockWrite += e.Value
+		}
+	}
+
+	return sample
+}
+
+// cpuPercent returns the CPU usage between the previous and the current stats,
+// using the same formula as docker stats.
+func cpuPercent(cur container.CPUStats, prev container.CPUStats) float64 {
+	cpuDelta := float64(cur.CPUUsage.TotalUsage) - float64(prev.CPUUsage.TotalUsage)
+	systemDelta := float64(cur.SystemUsage) - float64(prev.SystemUsage)
+	if prev.SystemUsage == 0 || cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	onlineCPUs := float64(cur.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(cur.CPUUsage.PercpuUsage))
+	}
+
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// memoryUsage returns the memory usage without the inactive page cache,
+// using the same formula as docker stats for cgroup v1 and v2.
+func memoryUsage(m container.MemoryStats) uint64 {
+	cache, ok := m.Stats["total_inactive_file"] // cgroup v1
+	if !ok {
+		cache = m.Stats["inactive_file"] // cgroup v2
+	}
+
+	if cache > m.Usage {
+		return m.Usage
+	}
+
+	return m.Usage - cache
+}
+
+// StatsStream is a stream of resource usage samples of a container,
+// returned by [DockerContainer.Stats].
+type StatsStream struct {
+	body io.ReadCloser
+	dec  *json.Decoder
+}
+
+// Stats returns a stream of resource usage samples of the container,
+// which the daemon produces about once per second.
+// The stream must be closed when no longer needed, and it's closed
+// automatically when ctx is done.
+func (c *DockerContainer) Stats(ctx context.Context) (*StatsStream, error) {
+	res, err := c.provider.client.ContainerStats(ctx, c.ID, client.ContainerStatsOptions{
+		Stream: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("container stats: %w", err)
+	}
+
+	return &StatsStream{
+		body: res.Body,
+		dec:  json.NewDecoder(res.Body),
+	}, nil
+}
+
+// Next blocks until the next sample is available and returns it.
+// It returns [io.EOF] when the stream ends, for instance because
+// the container stopped, and [io.ErrUnexpectedEOF] if it's cut in
+// the middle of a sample.
+func (s *StatsStream) Next() (StatsSample, error) {
+	var stats container.StatsResponse
+	if err := s.dec.Decode(&stats); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return StatsSample{}, fmt.Errorf("truncated stats stream: %w", err)
+		}
+
+		return StatsSample{}, err
+	}
+
+	return newStatsSample(stats), nil
+}
+
+// Close closes the stream.
+func (s *StatsStream) Close() error {
+	return s.body.Close()
+}
+
+// StatsSampler records the resource usage samples of a container in the
+// background, started with [DockerContainer.SampleStats].
+type StatsSampler struct {
+	mtx     sync.Mutex // protects samples
+	samples []StatsSample
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// SampleStats starts recording the resource usage samples of the container in
+// the background, until [StatsSampler.Stop] is called, ctx is done or the
+// container stops.
+func (c *DockerContainer) SampleStats(ctx context.Context) (*StatsSampler, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream, err := c.Stats(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	s := &StatsSampler{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(s.done)
+		defer stream.Close()
+
+		for {
+			sample, err := stream.Next()
+			if err != nil {
+				if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+					s.err = err
+				}
+				return
+			}
+
+			s.mtx.Lock()
+			s.samples = append(s.samples, sample)
+			s.mtx.Unlock()
+		}
+	}()
+
+	return s, nil
+}
+
+// Stop stops recording samples, returning the error that stopped
+// the recording, if any. The recorded samples are kept.
+func (s *StatsSampler) Stop() error {
+	s.cancel()
+	<-s.done
+
+	return s.err
+}
+
+// Samples returns a copy of the samples recorded so far.
+func (s *StatsSampler) Samples() []StatsSample {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	samples := make([]StatsSample, len(s.samples))
+	copy(samples, s.samples)
+
+	return samples
+}
+
+// PeakMemory returns the highest memory usage recorded so far, in bytes.
+func (s *StatsSampler) PeakMemory() uint64 {
+	var peak uint64
+	for _, sample := range s.Samples() {
+		peak = max(peak, sample.MemoryUsage)
+	}
+
+	return peak
+}
+
+// MemoryGrowth returns the difference between the last and the first memory
+// usage recorded so far, in bytes. It's negative if the usage decreased.
+func (s *StatsSampler) MemoryGrowth() int64 {
+	samples := s.Samples()
+	if len(samples) < 2 {
+		return 0
+	}
+
+	return int64(samples[len(samples)-1].MemoryUsage) - int64(samples[0].MemoryUsage)
+}
+
+// statsCSVHeader is the header of the CSV reports.
+var statsCSVHeader = []string{
+	"time", "cpu_percent", "memory_usage", "memory_limit",
+	"network_rx", "network_tx", "block_read", "block_write", "pids",
+}
+
+// WriteCSV writes the samples recorded so far to w as CSV, with a header row.
+func (s *StatsSampler) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(statsCSVHeader); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+
+	for _, sample := range s.Samples() {
+		record := []string{
+			sample.Time.Format(time.RFC3339Nano),
+			strconv.FormatFloat(sample.CPUPercent, 'f', 2, 64),
+			strconv.FormatUint(sample.MemoryUsage, 10),
+			strconv.FormatUint(sample.MemoryLimit, 10),
+			strconv.FormatUint(sample.NetworkRx, 10),
+			strconv.FormatUint(sample.NetworkTx, 10),
+			strconv.FormatUint(sample.BlockRead, 10),
+			strconv.FormatUint(sample.BlockWrite, 10),
+			strconv.FormatUint(sample.PIDs, 10),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("write csv: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+
+	return nil
+}
+
+// WriteJSON writes the samples recorded so far to w as a JSON array.
+func (s *StatsSampler) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	samples := s.Samples()
+	if samples == nil {
+		samples = []StatsSample{}
+	}
+
+	if err := enc.Encode(samples); err != nil {
+		return fmt.Errorf("write json: %w", err)
+	}
+
+	return nil
+}
+
+// WriteReports writes the samples recorded so far to the name.csv and name.json
+// files in dir, which is created if it does not exist.
+func (s *StatsSampler) WriteReports(dir string, name string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	write := func(ext string, fn func(io.Writer) error) error {
+		f, err := os.Create(filepath.Join(dir, name+ext))
+		if err != nil {
+			return fmt.Errorf("create report: %w", err)
+		}
+
+		return errors.Join(fn(f), f.Close())
+	}
+
+	if err := write(".csv", s.WriteCSV); err != nil {
+		return err
+	}
+
+	return write(".json", s.WriteJSON)
+}
@@ -0,0 +1,170 @@
+package testcontainers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStatsSample(t *testing.T) {
+	read := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	sample := newStatsSample(container.StatsResponse{
+		Read: read,
+		CPUStats: container.CPUStats{
+			CPUUsage:    container.CPUUsage{TotalUsage: 300},
+			SystemUsage: 2000,
+			OnlineCPUs:  4,
+		},
+		PreCPUStats: container.CPUStats{
+			CPUUsage:    container.CPUUsage{TotalUsage: 100},
+			SystemUsage: 1000,
+		},
+		MemoryStats: container.MemoryStats{
+			Usage: 1000,
+			Limit: 4000,
+			Stats: map[string]uint64{"inactive_file": 200},
+		},
+		Networks: map[string]container.NetworkStats{
+			"eth0": {RxBytes: 10, TxBytes: 20},
+			"eth1": {RxBytes: 1, TxBytes: 2},
+		},
+		BlkioStats: container.BlkioStats{
+			IoServiceBytesRecursive: []container.BlkioStatEntry{
+				{Op: "read", Value: 5},
+				{Op: "Write", Value: 7},
+				{Op: "total", Value: 12},
+			},
+		},
+		PidsStats: container.PidsStats{Current: 3},
+	})
+
+	require.Equal(t, StatsSample{
+		Time:        read,
+		CPUPercent:  80,
+		MemoryUsage: 800,
+		MemoryLimit: 4000,
+		NetworkRx:   11,
+		NetworkTx:   22,
+		BlockRead:   5,
+		BlockWrite:  7,
+		PIDs:        3,
+	}, sample)
+
+	t.Run("first-sample", func(t *testing.T) {
+		sample := newStatsSample(container.StatsResponse{
+			CPUStats: container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 300}, SystemUsage: 2000},
+		})
+		require.Zero(t, sample.CPUPercent)
+	})
+
+	t.Run("cgroup-v1", func(t *testing.T) {
+		usage := memoryUsage(container.MemoryStats{
+			Usage: 1000,
+			Stats: map[string]uint64{"total_inactive_file": 300, "inactive_file": 100},
+		})
+		require.Equal(t, uint64(700), usage)
+	})
+}
+
+func TestStatsStreamNext(t *testing.T) {
+	newStream := func(body string) *StatsStream {
+		r := io.NopCloser(strings.NewReader(body))
+		return &StatsStream{body: r, dec: json.NewDecoder(r)}
+	}
+
+	t.Run("eof", func(t *testing.T) {
+		stream := newStream(`{"read":"2024-01-02T03:04:05Z"}` + "\n")
+
+		_, err := stream.Next()
+		require.NoError(t, err)
+
+		_, err = stream.Next()
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		stream := newStream(`{"read":"2024-01-02T03:04:05Z"}` + "\n" + `{"read":"2024-01-02T03:`)
+
+		_, err := stream.Next()
+		require.NoError(t, err)
+
+		_, err = stream.Next()
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		require.NotErrorIs(t, err, io.EOF)
+	})
+}
+
+func TestStatsSamplerReports(t *testing.T) {
+	read := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	s := &StatsSampler{samples: []StatsSample{
+		{Time: read, CPUPercent: 1.5, MemoryUsage: 100, MemoryLimit: 1000},
+		{Time: read.Add(time.Second), CPUPercent: 2.25, MemoryUsage: 300, MemoryLimit: 1000},
+		{Time: read.Add(2 * time.Second), CPUPercent: 0, MemoryUsage: 250, MemoryLimit: 1000},
+	}}
+
+	require.Equal(t, uint64(300), s.PeakMemory())
+	require.Equal(t, int64(150), s.MemoryGrowth())
+
+	var buf bytes.Buffer
+	require.NoError(t, s.WriteCSV(&buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, "time,cpu_percent,memory_usage,memory_limit,network_rx,network_tx,block_read,block_write,pids", lines[0])
+	require.Equal(t, "2024-01-02T03:04:06Z,2.25,300,1000,0,0,0,0,0", lines[2])
+
+	dir := filepath.Join(t.TempDir(), "reports")
+	require.NoError(t, s.WriteReports(dir, "soak"))
+
+	b, err := os.ReadFile(filepath.Join(dir, "soak.json"))
+	require.NoError(t, err)
+
+	var samples []StatsSample
+	require.NoError(t, json.Unmarshal(b, &samples))
+	require.Equal(t, s.Samples(), samples)
+
+	b, err = os.ReadFile(filepath.Join(dir, "soak.csv"))
+	require.NoError(t, err)
+	require.Equal(t, buf.String(), string(b))
+}
+
+func TestContainerStats(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := Run(ctx, nginxAlpineImage)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	t.Run("stream", func(t *testing.T) {
+		stream, err := ctr.Stats(ctx)
+		require.NoError(t, err)
+		defer stream.Close()
+
+		sample, err := stream.Next()
+		require.NoError(t, err)
+		require.NotZero(t, sample.MemoryUsage)
+		require.NotZero(t, sample.PIDs)
+	})
+
+	t.Run("sampler", func(t *testing.T) {
+		sampler := RecordStats(t, ctr, t.TempDir())
+
+		require.Eventually(t, func() bool {
+			return len(sampler.Samples()) >= 2
+		}, 10*time.Second, 100*time.Millisecond)
+
+		require.NotZero(t, sampler.PeakMemory())
+		AssertMemoryBelow(t, sampler, 1<<30)
+	})
+}
@@ -1993,3 +1993,75 @@ func TestMappedPortEmptyString(t *testing.T) {
 	require.True(t, port.IsZero(), "expected zero port for empty string input")
 	require.ErrorIs(t, err, errdefs.ErrNotFound)
 }
+
+func TestDockerContainer_Restart(t *testing.T) {
+	t.Run("stable-ports", func(t *testing.T) {
+		ctr, err := Run(context.Background(), nginxAlpineImage,
+			WithExposedPorts(nginxDefaultPort),
+			WithStablePorts(),
+			WithWaitStrategy(wait.ForListeningPort(nginxDefaultPort)),
+		)
+		CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		endpoint, err := ctr.PortEndpoint(context.Background(), nginxDefaultPort, "http")
+		require.NoError(t, err)
+
+		require.NoError(t, ctr.Restart(context.Background()))
+
+		restarted, err := ctr.PortEndpoint(context.Background(), nginxDefaultPort, "http")
+		require.NoError(t, err)
+		require.Equal(t, endpoint, restarted)
+
+		resp, err := http.Get(restarted)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("random-ports", func(t *testing.T) {
+		ctr, err := Run(context.Background(), nginxAlpineImage,
+			WithExposedPorts(nginxDefaultPort),
+			WithWaitStrategy(wait.ForListeningPort(nginxDefaultPort)),
+		)
+		CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		require.NoError(t, ctr.Restart(context.Background()))
+
+		state, err := ctr.State(context.Background())
+		require.NoError(t, err)
+		require.True(t, state.Running)
+	})
+}
+
+func TestDockerContainer_StopKeepsHostAccess(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	ctr, err := Run(context.Background(), alpineImage,
+		WithHostPortAccess(listener.Addr().(*net.TCPAddr).Port),
+		WithCmd("top"),
+	)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	ctr.hostAccessMtx.Lock()
+	sshd := ctr.hostAccess
+	ctr.hostAccessMtx.Unlock()
+	require.NotNil(t, sshd)
+
+	// The tunnel is kept while the container is stopped, and not terminated.
+	require.NoError(t, ctr.Stop(context.Background(), nil))
+
+	state, err := sshd.State(context.Background())
+	require.NoError(t, err)
+	require.True(t, state.Running)
+
+	// It's removed with the container.
+	require.NoError(t, ctr.Terminate(context.Background()))
+
+	_, err = sshd.State(context.Background())
+	require.True(t, errdefs.IsNotFound(err), err)
+}
@@ -0,0 +1,62 @@
+package testcontainers
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/moby/moby/api/types/network"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostPortBinding(t *testing.T) {
+	dualStack := []network.PortBinding{
+		{HostIP: netip.IPv4Unspecified(), HostPort: "32768"},
+		{HostIP: netip.IPv6Unspecified(), HostPort: "32769"},
+	}
+
+	t.Run("host-name", func(t *testing.T) {
+		require.Equal(t, "32768", hostPortBinding(dualStack, "localhost").HostPort)
+	})
+
+	t.Run("ipv4-host", func(t *testing.T) {
+		require.Equal(t, "32768", hostPortBinding(dualStack, "127.0.0.1").HostPort)
+	})
+
+	t.Run("ipv6-host", func(t *testing.T) {
+		require.Equal(t, "32769", hostPortBinding(dualStack, "::1").HostPort)
+	})
+
+	t.Run("bracketed-ipv6-host", func(t *testing.T) {
+		require.Equal(t, "32769", hostPortBinding(dualStack, "[::1]").HostPort)
+	})
+
+	t.Run("scoped-ipv6-host", func(t *testing.T) {
+		require.Equal(t, "32769", hostPortBinding(dualStack, "fe80::1%eth0").HostPort)
+	})
+
+	t.Run("ipv6-first", func(t *testing.T) {
+		bindings := []network.PortBinding{dualStack[1], dualStack[0]}
+		require.Equal(t, "32768", hostPortBinding(bindings, "localhost").HostPort)
+		require.Equal(t, "32769", hostPortBinding(bindings, "::1").HostPort)
+	})
+
+	t.Run("no-host-ip", func(t *testing.T) {
+		bindings := []network.PortBinding{{HostPort: "32770"}}
+		require.Equal(t, "32770", hostPortBinding(bindings, "::1").HostPort)
+	})
+
+	t.Run("ipv4-only", func(t *testing.T) {
+		bindings := dualStack[:1]
+		require.Equal(t, "32768", hostPortBinding(bindings, "::1").HostPort)
+	})
+}
+
+func TestStartContainerFailDueToPortConflictRegex(t *testing.T) {
+	require.True(t, startContainerFailDueToPortConflictRegex.MatchString(
+		"driver failed programming external connectivity on endpoint test: Bind for 0.0.0.0:32768 failed: port is already allocated",
+	))
+	require.True(t, startContainerFailDueToPortConflictRegex.MatchString(
+		"error starting userland proxy: listen tcp4 0.0.0.0:32768: bind: address already in use",
+	))
+	require.False(t, startContainerFailDueToPortConflictRegex.MatchString("No such image: nginx:alpine"))
+}
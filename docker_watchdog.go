@@ -0,0 +1,294 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+)
+
+// crashLogTailLines is the number of log lines recorded in a [CrashReport].
+const crashLogTailLines = 50
+
+// CrashReport describes an unexpected failure of a container, detected by the
+// watchdog enabled with [WithCrashWatchdog].
+type CrashReport struct {
+	// ContainerID is the ID of the container.
+	ContainerID string
+
+	// Time is the time of the event reporting the failure.
+	Time time.Time
+
+	// Reason is the Docker event reporting the failure:
+	// "die" or "health_status: unhealthy".
+	Reason string
+
+	// ExitCode is the exit code of the container, when it died.
+	ExitCode int
+
+	// OOMKilled is true if the container was killed because it ran out of memory.
+	OOMKilled bool
+
+	// LogTail is the last lines of the logs of the container, at the time of the failure.
+	LogTail string
+}
+
+// String returns a human-readable description of the crash, including the log tail.
+func (r CrashReport) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "container %s: %s", r.ContainerID[:min(12, len(r.ContainerID))], r.Reason)
+	if r.Reason == string(events.ActionDie) {
+		fmt.Fprintf(&sb, " with exit code %d", r.ExitCode)
+	}
+	if r.OOMKilled {
+		sb.WriteString(" (OOM killed)")
+	}
+	fmt.Fprintf(&sb, " at %s", r.Time.Format(time.RFC3339))
+
+	if r.LogTail != "" {
+		fmt.Fprintf(&sb, "\nlast %d log lines:\n%s", crashLogTailLines, r.LogTail)
+	}
+
+	return sb.String()
+}
+
+// WithCrashWatchdog enables a watchdog that reports unexpected failures of the
+// container while it's running: the container dying, being killed because it ran
+// out of memory, or becoming unhealthy. Stopping or terminating the container
+// is not considered a failure.
+//
+// The watched containers share a single subscription to the Docker events of the
+// containers of the test session, see [DockerProvider.Events], which is active
+// while at least one of them is running.
+//
+// The failures are available with [DockerContainer.Crashed], and are passed to
+// the onCrash callbacks, which are called from a separate goroutine.
+// See [FailOnCrash] to fail a test when a container crashed.
+func WithCrashWatchdog(onCrash ...func(CrashReport)) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		w := &crashWatchdog{onCrash: onCrash}
+
+		return WithAdditionalLifecycleHooks(ContainerLifecycleHooks{
+			PostStarts: []ContainerHook{
+				func(ctx context.Context, c Container) error {
+					dockerContainer := c.(*DockerContainer)
+					dockerContainer.watchdog.Store(w)
+					if err := sessionWatchdog.watch(ctx, dockerContainer, w); err != nil {
+						return fmt.Errorf("crash watchdog: %w", err)
+					}
+					return nil
+				},
+			},
+			PreStops: []ContainerHook{
+				func(_ context.Context, c Container) error {
+					sessionWatchdog.unwatch(c.GetContainerID())
+					return nil
+				},
+			},
+		})(req)
+	}
+}
+
+// Crashed returns the first failure detected by the watchdog enabled with
+// [WithCrashWatchdog], or nil if the container didn't fail or the watchdog
+// is not enabled.
+func (c *DockerContainer) Crashed() *CrashReport {
+	w := c.watchdog.Load()
+	if w == nil {
+		return nil
+	}
+
+	reports := w.crashes()
+	if len(reports) == 0 {
+		return nil
+	}
+
+	return &reports[0]
+}
+
+// Crashes returns all the failures detected by the watchdog enabled with
+// [WithCrashWatchdog].
+func (c *DockerContainer) Crashes() []CrashReport {
+	w := c.watchdog.Load()
+	if w == nil {
+		return nil
+	}
+
+	return w.crashes()
+}
+
+// sessionWatchdog is the singleton instance of crashWatchdogs.
+var sessionWatchdog = &crashWatchdogs{}
+
+// crashWatchdogs dispatches the Docker events of the containers of the test
+// session to the watchdogs of the running containers.
+type crashWatchdogs struct {
+	mtx        sync.Mutex // protects the fields below
+	containers map[string]*watchedContainer
+	cancel     context.CancelFunc
+}
+
+// watchedContainer is a running container with a crash watchdog.
+type watchedContainer struct {
+	ctr       *DockerContainer
+	watchdog  *crashWatchdog
+	startedAt time.Time
+	oomKilled bool
+}
+
+// watch starts dispatching the events of the container to w, subscribing to
+// the events of the session if it's the first watched container.
+// It's a no-op if the container is already watched.
+func (s *crashWatchdogs) watch(ctx context.Context, ctr *DockerContainer, w *crashWatchdog) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.containers[ctr.ID]; ok {
+		return nil
+	}
+
+	if s.containers == nil {
+		s.containers = make(map[string]*watchedContainer)
+	}
+
+	// The events of a previous run of the container are ignored.
+	inspect, err := ctr.Inspect(ctx)
+	if err != nil {
+		return fmt.Errorf("inspect: %w", err)
+	}
+	startedAt, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt)
+	if err != nil {
+		return fmt.Errorf("parse start time: %w", err)
+	}
+
+	if s.cancel == nil {
+		watchCtx, cancel := context.WithCancel(context.Background())
+		// The subscription is synchronous, so no event after the start of the container is missed.
+		events := ctr.provider.Events(watchCtx, EventDie, EventOOM, EventHealthStatus)
+		s.cancel = cancel
+
+		go s.dispatch(watchCtx, events)
+	}
+
+	s.containers[ctr.ID] = &watchedContainer{ctr: ctr, watchdog: w, startedAt: startedAt}
+
+	return nil
+}
+
+// unwatch stops dispatching the events of the container, so that stopping it
+// isn't reported as a failure. The subscription is cancelled once no container
+// is watched.
+func (s *crashWatchdogs) unwatch(id string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.containers[id]; !ok {
+		return
+	}
+
+	delete(s.containers, id)
+
+	if len(s.containers) == 0 {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// dispatch records the failures reported by the events of the watched containers,
+// until the events channel is closed.
+func (s *crashWatchdogs) dispatch(ctx context.Context, ch <-chan ContainerEvent) {
+	for e := range ch {
+		s.mtx.Lock()
+		if ctx.Err() != nil {
+			// Cancelled by unwatch, a new subscription may be dispatching the events.
+			s.mtx.Unlock()
+			return
+		}
+		wc := s.containers[e.ContainerID]
+		s.mtx.Unlock()
+
+		if wc == nil || e.Time.Before(wc.startedAt) {
+			continue
+		}
+
+		switch {
+		case e.Kind == EventOOM:
+			// The die event follows.
+			wc.oomKilled = true
+		case e.Kind == EventDie:
+			wc.watchdog.record(ctx, wc.ctr, CrashReport{
+				Time:      e.Time,
+				Reason:    string(events.ActionDie),
+				ExitCode:  e.ExitCode,
+				OOMKilled: wc.oomKilled,
+			})
+			wc.oomKilled = false
+		case e.Kind == EventHealthStatus && e.HealthStatus == string(container.Unhealthy):
+			wc.watchdog.record(ctx, wc.ctr, CrashReport{
+				Time:   e.Time,
+				Reason: string(events.ActionHealthStatusUnhealthy),
+			})
+		}
+	}
+}
+
+// crashWatchdog records the failures of a container.
+type crashWatchdog struct {
+	onCrash []func(CrashReport)
+
+	mtx     sync.Mutex // protects the fields below
+	reports []CrashReport
+}
+
+// crashes returns a copy of the recorded failures.
+func (w *crashWatchdog) crashes() []CrashReport {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	reports := make([]CrashReport, len(w.reports))
+	copy(reports, w.reports)
+
+	return reports
+}
+
+// record completes the report with the log tail of the container, records it
+// and calls the callbacks.
+func (w *crashWatchdog) record(ctx context.Context, ctr *DockerContainer, report CrashReport) {
+	report.ContainerID = ctr.ID
+
+	tail, err := ctr.logTail(ctx, crashLogTailLines)
+	if err != nil {
+		ctr.logger.Printf("crash watchdog of container %s: log tail: %v", ctr.ID[:12], err)
+	}
+	report.LogTail = tail
+
+	w.mtx.Lock()
+	w.reports = append(w.reports, report)
+	w.mtx.Unlock()
+
+	for _, fn := range w.onCrash {
+		fn(report)
+	}
+}
+
+// logTail returns the last n lines of the logs of the container.
+func (c *DockerContainer) logTail(ctx context.Context, n int) (string, error) {
+	r, err := c.LogsWithOptions(ctx, LogsOptions{Tail: n})
+	if err != nil {
+		return "", fmt.Errorf("container logs: %w", err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("read logs: %w", err)
+	}
+
+	return strings.TrimRight(string(b), "\n"), nil
+}
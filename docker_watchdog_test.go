@@ -0,0 +1,140 @@
+package testcontainers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestCrashReport_String(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	report := CrashReport{
+		ContainerID: "0123456789abcdef",
+		Time:        at,
+		Reason:      "die",
+		ExitCode:    137,
+		OOMKilled:   true,
+		LogTail:     "out of memory",
+	}
+	require.Equal(t, "container 0123456789ab: die with exit code 137 (OOM killed) at 2024-01-02T03:04:05Z\nlast 50 log lines:\nout of memory", report.String())
+
+	report = CrashReport{
+		ContainerID: "0123456789abcdef",
+		Time:        at,
+		Reason:      "health_status: unhealthy",
+	}
+	require.Equal(t, "container 0123456789ab: health_status: unhealthy at 2024-01-02T03:04:05Z", report.String())
+}
+
+func TestCrashWatchdog(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("crash", func(t *testing.T) {
+		crashes := make(chan CrashReport, 1)
+
+		ctr, err := Run(ctx, alpineImage,
+			WithCmd("sh", "-c", "echo ready; read -r _; echo boom; exit 3"),
+			WithConfigModifier(func(c *container.Config) {
+				c.OpenStdin = true
+			}),
+			WithWaitStrategy(wait.ForLog("ready")),
+			WithCrashWatchdog(func(r CrashReport) {
+				crashes <- r
+			}),
+		)
+		CleanupContainer(t, ctr)
+		require.NoError(t, err)
+		require.Nil(t, ctr.Crashed())
+
+		// Unblock the read of the shell.
+		attach, err := ctr.provider.client.ContainerAttach(ctx, ctr.ID, client.ContainerAttachOptions{
+			Stream: true,
+			Stdin:  true,
+		})
+		require.NoError(t, err)
+		_, err = attach.Conn.Write([]byte("\n"))
+		require.NoError(t, err)
+		attach.Close()
+
+		select {
+		case report := <-crashes:
+			require.Equal(t, "die", report.Reason)
+			require.Equal(t, 3, report.ExitCode)
+			require.False(t, report.OOMKilled)
+			require.Contains(t, report.LogTail, "boom")
+		case <-time.After(30 * time.Second):
+			t.Fatal("crash not reported")
+		}
+
+		crashed := ctr.Crashed()
+		require.NotNil(t, crashed)
+		require.Equal(t, 3, crashed.ExitCode)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		ctr, err := Run(ctx, nginxAlpineImage, WithCrashWatchdog())
+		CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		FailOnCrash(t, ctr)
+
+		require.NoError(t, ctr.Stop(ctx, nil))
+		require.NoError(t, ctr.Start(ctx))
+		require.Nil(t, ctr.Crashed())
+	})
+	t.Run("shared", func(t *testing.T) {
+		crashes := make(chan CrashReport, 2)
+		onCrash := func(r CrashReport) {
+			crashes <- r
+		}
+
+		ctr1, err := Run(ctx, alpineImage,
+			WithCmd("sh", "-c", "echo ready; sleep 300"),
+			WithWaitStrategy(wait.ForLog("ready")),
+			WithCrashWatchdog(onCrash),
+		)
+		CleanupContainer(t, ctr1)
+		require.NoError(t, err)
+
+		ctr2, err := Run(ctx, alpineImage,
+			WithCmd("sh", "-c", "echo ready; sleep 300"),
+			WithWaitStrategy(wait.ForLog("ready")),
+			WithCrashWatchdog(onCrash),
+		)
+		CleanupContainer(t, ctr2)
+		require.NoError(t, err)
+
+		sessionWatchdog.mtx.Lock()
+		require.Contains(t, sessionWatchdog.containers, ctr1.ID)
+		require.Contains(t, sessionWatchdog.containers, ctr2.ID)
+		sessionWatchdog.mtx.Unlock()
+
+		// Killing the container isn't stopping it, so it's a crash.
+		_, err = ctr2.provider.client.ContainerKill(ctx, ctr2.ID, client.ContainerKillOptions{Signal: "SIGKILL"})
+		require.NoError(t, err)
+
+		select {
+		case report := <-crashes:
+			require.Equal(t, ctr2.ID, report.ContainerID)
+			require.Equal(t, 137, report.ExitCode)
+		case <-time.After(30 * time.Second):
+			t.Fatal("crash not reported")
+		}
+
+		require.Nil(t, ctr1.Crashed())
+		require.NotNil(t, ctr2.Crashed())
+
+		require.NoError(t, ctr1.Stop(ctx, nil))
+		sessionWatchdog.mtx.Lock()
+		require.NotContains(t, sessionWatchdog.containers, ctr1.ID)
+		sessionWatchdog.mtx.Unlock()
+		require.Nil(t, ctr1.Crashed())
+	})
+}
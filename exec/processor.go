@@ -61,6 +61,23 @@ func WithEnv(env []string) ProcessOption {
 	})
 }
 
+// WithTTY returns a [ProcessOption] that allocates a pseudo-TTY for the process.
+// With a TTY attached, stdout and stderr are merged by the terminal into a single
+// raw stream, so the output is not multiplexed.
+func WithTTY() ProcessOption {
+	return ProcessOptionFunc(func(opts *ProcessOptions) {
+		opts.ExecConfig.TTY = true
+	})
+}
+
+// WithConsoleSize returns a [ProcessOption] that sets the initial size of the
+// pseudo-TTY, in rows and columns. It is only valid together with [WithTTY].
+func WithConsoleSize(height, width uint) ProcessOption {
+	return ProcessOptionFunc(func(opts *ProcessOptions) {
+		opts.ExecConfig.ConsoleSize = client.ConsoleSize{Height: height, Width: width}
+	})
+}
+
 // safeBuffer is a goroutine safe buffer.
 type safeBuffer struct {
 	mtx sync.Mutex
@@ -109,6 +126,11 @@ func Multiplexed() ProcessOption {
 			return
 		}
 
+		// a TTY produces a raw stream, there are no headers to strip.
+		if opts.ExecConfig.TTY {
+			return
+		}
+
 		done := make(chan struct{})
 
 		var outBuff safeBuffer
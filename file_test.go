@@ -187,3 +187,51 @@ func untar(dst string, r io.Reader) error {
 		}
 	}
 }
+
+func Test_UntarDir(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	entries := []struct {
+		hdr     tar.Header
+		content string
+	}{
+		{hdr: tar.Header{Name: "reports/", Typeflag: tar.TypeDir, Mode: 0o755}},
+		{hdr: tar.Header{Name: "reports/index.html", Typeflag: tar.TypeReg, Mode: 0o644}, content: "index"},
+		{hdr: tar.Header{Name: "reports/sub/", Typeflag: tar.TypeDir, Mode: 0o755}},
+		{hdr: tar.Header{Name: "reports/sub/data.json", Typeflag: tar.TypeReg, Mode: 0o600}, content: "{}"},
+		{hdr: tar.Header{Name: "reports/link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}},
+		{hdr: tar.Header{Name: "reports/../escape.txt", Typeflag: tar.TypeReg, Mode: 0o644}, content: "escape"},
+		{hdr: tar.Header{Name: "other/file.txt", Typeflag: tar.TypeReg, Mode: 0o644}, content: "other"},
+	}
+	for _, e := range entries {
+		e.hdr.Size = int64(len(e.content))
+		require.NoError(t, tw.WriteHeader(&e.hdr))
+		_, err := tw.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	parent := t.TempDir()
+	dst := filepath.Join(parent, "out")
+	require.NoError(t, os.Mkdir(dst, 0o755))
+
+	require.NoError(t, untarDir(tar.NewReader(&buf), "reports", dst))
+
+	b, err := os.ReadFile(filepath.Join(dst, "index.html"))
+	require.NoError(t, err)
+	require.Equal(t, "index", string(b))
+
+	b, err = os.ReadFile(filepath.Join(dst, "sub", "data.json"))
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(b))
+
+	_, err = os.Lstat(filepath.Join(dst, "link"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = os.Stat(filepath.Join(parent, "escape.txt"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = os.Stat(filepath.Join(dst, "file.txt"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
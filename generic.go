@@ -16,6 +16,11 @@ var (
 	ErrReuseEmptyName = errors.New("with reuse option a container name mustn't be empty")
 )
 
+// maxStablePortsAttempts is the maximum number of attempts to start a container
+// whose host ports, pinned by [WithStablePorts], are taken by another process in
+// the short time between their release and the start of the container.
+const maxStablePortsAttempts = 3
+
 // GenericContainerRequest represents parameters to a generic container
 type GenericContainerRequest struct {
 	ContainerRequest              // embedded request for provider
@@ -64,28 +69,47 @@ func GenericContainer(ctx context.Context, req GenericContainerRequest) (Contain
 	}
 	defer provider.Close()
 
-	var c Container
 	if req.Reuse {
 		// we must protect the reusability of the container in the case it's invoked
 		// in a parallel execution, via ParallelContainers or t.Parallel()
 		reuseContainerMx.Lock()
 		defer reuseContainerMx.Unlock()
-
-		c, err = provider.ReuseOrCreateContainer(ctx, req.ContainerRequest)
-	} else {
-		c, err = provider.CreateContainer(ctx, req.ContainerRequest)
-	}
-	if err != nil {
-		// At this point `c` might not be nil. Give the caller an opportunity to call Destroy on the container.
-		return c, fmt.Errorf("create container: %w", err)
 	}
 
-	if req.Started && !c.IsRunning() {
-		if err := c.Start(ctx); err != nil {
+	for attempt := 1; ; attempt++ {
+		var c Container
+		if req.Reuse {
+			c, err = provider.ReuseOrCreateContainer(ctx, req.ContainerRequest)
+		} else {
+			c, err = provider.CreateContainer(ctx, req.ContainerRequest)
+		}
+		if err != nil {
+			// At this point `c` might not be nil. Give the caller an opportunity to call Destroy on the container.
+			return c, fmt.Errorf("create container: %w", err)
+		}
+
+		if !req.Started || c.IsRunning() {
+			return c, nil
+		}
+
+		err = c.Start(ctx)
+		if err == nil {
+			return c, nil
+		}
+
+		// The host ports pinned by WithStablePorts are only released right before
+		// the container starts, but another process could still take one of them
+		// in the meantime, so recreate it with other ones.
+		if !req.StablePorts || req.Reuse || attempt == maxStablePortsAttempts ||
+			!startContainerFailDueToPortConflictRegex.MatchString(err.Error()) {
 			return c, fmt.Errorf("start container: %w", err)
 		}
+
+		logger.Printf("📌 Host port already in use, recreating the container: %s", err)
+		if errTerminate := TerminateContainer(c); errTerminate != nil {
+			return c, fmt.Errorf("start container: %w", errors.Join(err, errTerminate))
+		}
 	}
-	return c, nil
 }
 
 // GenericProvider represents an abstraction for container and network providers
@@ -85,6 +85,12 @@ type Config struct {
 	//
 	// Environment variable: TESTCONTAINERS_DOCKER_SOCKET_OVERRIDE
 	TestcontainersHost string `properties:"tc.host,default="`
+
+	// ArtifactsDir is the directory where the artifacts of the containers, such as their
+	// logs, are written when a test fails. Collecting artifacts is disabled if it's empty.
+	//
+	// Environment variable: TESTCONTAINERS_ARTIFACTS_DIR
+	ArtifactsDir string `properties:"artifacts.dir,default="`
 }
 
 // }
@@ -121,6 +127,11 @@ func read() Config {
 			config.HubImageNamePrefix = hubImageNamePrefix
 		}
 
+		artifactsDir := os.Getenv("TESTCONTAINERS_ARTIFACTS_DIR")
+		if artifactsDir != "" {
+			config.ArtifactsDir = artifactsDir
+		}
+
 		ryukPrivilegedEnv := os.Getenv("TESTCONTAINERS_RYUK_CONTAINER_PRIVILEGED")
 		if parseBool(ryukPrivilegedEnv) {
 			config.RyukPrivileged = ryukPrivilegedEnv == "true"
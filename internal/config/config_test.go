@@ -21,6 +21,7 @@ const (
 func resetTestEnv(t *testing.T) {
 	t.Helper()
 	t.Setenv("TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX", "")
+	t.Setenv("TESTCONTAINERS_ARTIFACTS_DIR", "")
 	t.Setenv("TESTCONTAINERS_RYUK_DISABLED", "")
 	t.Setenv("TESTCONTAINERS_RYUK_CONTAINER_PRIVILEGED", "")
 	t.Setenv("RYUK_VERBOSE", "")
@@ -504,6 +505,28 @@ func TestReadTCConfig(t *testing.T) {
 					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
 				},
 			},
+			{
+				"With artifacts dir set as a property",
+				`artifacts.dir=/tmp/props`,
+				map[string]string{},
+				Config{
+					ArtifactsDir:            "/tmp/props",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With artifacts dir set as env var and properties: Env var wins",
+				`artifacts.dir=/tmp/props`,
+				map[string]string{
+					"TESTCONTAINERS_ARTIFACTS_DIR": "/tmp/env",
+				},
+				Config{
+					ArtifactsDir:            "/tmp/env",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
 			{
 				"With Hub image name prefix set as env var and properties: Env var wins",
 				`hub.image.name.prefix=` + defaultHubPrefix + `/props/`,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/netip"
 	"os"
 	"os/exec"
 	"strings"
@@ -51,12 +52,51 @@ func DefaultGatewayIP() (string, error) {
 		return "", errors.New("failed to detect docker host")
 	}
 	ip := strings.TrimSpace(string(stdout))
+	if len(ip) == 0 {
+		// No IPv4 default route, the host may be IPv6-only.
+		stdout, err = exec.Command("ip", "-6", "route", "show", "default").Output()
+		if err == nil {
+			ip = parseIPv6DefaultGateway(string(stdout))
+		}
+	}
 	if len(ip) == 0 {
 		return "", errors.New("failed to parse default gateway IP")
 	}
 	return ip, nil
 }
 
+// parseIPv6DefaultGateway returns the gateway of the first default route
+// in the output of "ip -6 route show default", or an empty string if none.
+// A link-local gateway, as advertised by most IPv6 routers, is scoped
+// with the interface of the route, so that it can be dialed.
+func parseIPv6DefaultGateway(routes string) string {
+	for _, line := range strings.Split(routes, "\n") {
+		var via, dev string
+		fields := strings.Fields(line)
+		for i := 0; i+1 < len(fields); i++ {
+			switch fields[i] {
+			case "via":
+				via = fields[i+1]
+			case "dev":
+				dev = fields[i+1]
+			}
+		}
+
+		gw, err := netip.ParseAddr(via)
+		if err != nil || !gw.Is6() {
+			continue
+		}
+
+		if gw.IsLinkLocalUnicast() && dev != "" {
+			gw = gw.WithZone(dev)
+		}
+
+		return gw.String()
+	}
+
+	return ""
+}
+
 // dockerHostCheck Use a vanilla Docker client to check if the Docker host is reachable.
 // It will avoid recursive calls to this function.
 var dockerHostCheck = func(ctx context.Context, host string) error {
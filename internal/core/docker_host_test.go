@@ -482,6 +482,44 @@ func TestExtractDockerSocketFromClient(t *testing.T) {
 	})
 }
 
+func TestParseIPv6DefaultGateway(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes string
+		want   string
+	}{
+		{
+			name:   "global",
+			routes: "default via 2001:db8::1 dev eth0 metric 1024 pref medium\n",
+			want:   "2001:db8::1",
+		},
+		{
+			name:   "link-local",
+			routes: "default via fe80::1 dev eth0 proto ra metric 100 expires 1798sec pref medium\n",
+			want:   "fe80::1%eth0",
+		},
+		{
+			name: "first-route",
+			routes: "default via fe80::1 dev eth0 proto ra metric 100 pref medium\n" +
+				"default via fe80::2 dev eth1 proto ra metric 200 pref medium\n",
+			want: "fe80::1%eth0",
+		},
+		{
+			name:   "no-gateway",
+			routes: "default dev wg0 metric 1024 pref medium\n",
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, parseIPv6DefaultGateway(tc.routes))
+		})
+	}
+}
+
 func TestInAContainer(t *testing.T) {
 	t.Run("file does not exist", func(t *testing.T) {
 		tmpDir := t.TempDir()
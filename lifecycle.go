@@ -5,14 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/netip"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/network"
 
+	"github.com/testcontainers/testcontainers-go/internal/core"
 	"github.com/testcontainers/testcontainers-go/log"
+	"github.com/testcontainers/testcontainers-go/wait"
 )
 
 // ContainerRequestHook is a hook that will be called before a container is created.
@@ -227,7 +234,11 @@ var defaultReadinessHook = func() ContainerLifecycleHooks {
 						"⏳ Waiting for container id %s image: %s. Waiting for: %+v",
 						dockerContainer.ID[:12], dockerContainer.Image, strategyDesc,
 					)
-					if err := strategy.WaitUntilReady(ctx, dockerContainer); err != nil {
+					if err := wait.WaitUntilReady(ctx, strategy, dockerContainer); err != nil {
+						var waitErr *wait.WaitError
+						if errors.As(err, &waitErr) {
+							dockerContainer.logger.Printf("🚨 Container is not ready: %s\n%s", dockerContainer.ID[:12], waitErr.Summary())
+						}
 						return fmt.Errorf("wait until ready: %w", err)
 					}
 				}
@@ -351,10 +362,22 @@ func (c *DockerContainer) terminatedHook(ctx context.Context) error {
 	})
 }
 
+// addLifecycleHooks adds the lifecycle hooks to the container, once created.
+func (c *DockerContainer) addLifecycleHooks(hooks ContainerLifecycleHooks) {
+	c.lifecycleHooksMtx.Lock()
+	defer c.lifecycleHooksMtx.Unlock()
+
+	c.lifecycleHooks = append(c.lifecycleHooks, hooks)
+}
+
 // applyLifecycleHooks applies all lifecycle hooks reporting the container logs on error if logError is true.
 func (c *DockerContainer) applyLifecycleHooks(ctx context.Context, logError bool, hooks func(lifecycleHooks ContainerLifecycleHooks) []ContainerHook) error {
+	c.lifecycleHooksMtx.Lock()
+	allHooks := slices.Clone(c.lifecycleHooks)
+	c.lifecycleHooksMtx.Unlock()
+
 	var errs []error
-	for _, lifecycleHooks := range c.lifecycleHooks {
+	for _, lifecycleHooks := range allHooks {
 		if err := containerHookFn(ctx, hooks(lifecycleHooks))(c); err != nil {
 			errs = append(errs, err)
 		}
@@ -544,6 +567,7 @@ func (p *DockerProvider) preCreateContainerHook(ctx context.Context, req Contain
 
 	dockerInput.ExposedPorts = exposedPortSet
 	hostConfig.PortBindings = mergePortBindings(hostConfig.PortBindings, exposedPortSet)
+
 	return nil
 }
 
@@ -649,6 +673,109 @@ func mergePortBindings(configPortMap network.PortMap, exposedPortSet network.Por
 	return exposedPortMap
 }
 
+// portReservation holds the host ports picked for the port bindings of a
+// container until it starts, so that no other process takes them meanwhile.
+type portReservation struct {
+	once    sync.Once
+	closers []io.Closer
+}
+
+// release frees the reserved host ports, so that Docker can bind them.
+// It's a no-op if r is nil or the ports were already released.
+func (r *portReservation) release() {
+	if r == nil {
+		return
+	}
+
+	r.once.Do(func() {
+		for _, closer := range r.closers {
+			_ = closer.Close()
+		}
+	})
+}
+
+// reservePorts reserves free host ports for the ephemeral port bindings, for
+// [WithStablePorts]. The daemon must share the network of the host running the
+// tests, otherwise the free ports of the host are meaningless to it.
+func (p *DockerProvider) reservePorts(portMap network.PortMap) (*portReservation, error) {
+	if err := checkLocalDaemon(p.client.DaemonHost(), core.InAContainer()); err != nil {
+		return nil, fmt.Errorf("stable ports: %w", err)
+	}
+
+	reservation, err := reservePortBindings(portMap)
+	if err != nil {
+		return nil, fmt.Errorf("stable ports: %w", err)
+	}
+
+	return reservation, nil
+}
+
+// reservePortBindings replaces the ephemeral host ports of the bindings with free
+// ports of the host, so that they don't change when the container is restarted.
+//
+// Docker doesn't allow changing the port bindings of an existing container,
+// so they must be pinned before it's created. The picked ports stay bound until
+// the returned reservation is released, right before the container starts.
+//
+// The bindings are copied, so that reserving them again picks other ports.
+func reservePortBindings(portMap network.PortMap) (_ *portReservation, err error) {
+	reservation := &portReservation{}
+	defer func() {
+		if err != nil {
+			reservation.release()
+		}
+	}()
+
+	for p, bindings := range portMap {
+		bindings = slices.Clone(bindings)
+		portMap[p] = bindings
+		for i := range bindings {
+			if bindings[i].HostPort != "0" && bindings[i].HostPort != "" {
+				continue
+			}
+
+			hostPort, closer, err := reserveHostPort(p.Proto(), bindings[i].HostIP)
+			if err != nil {
+				return nil, fmt.Errorf("reserve host port for %s: %w", p, err)
+			}
+
+			reservation.closers = append(reservation.closers, closer)
+			bindings[i].HostPort = hostPort
+		}
+	}
+
+	return reservation, nil
+}
+
+// reserveHostPort binds a free port of the host for the protocol, on the host IP
+// or on all the interfaces if it's not valid, returning it with its listener.
+func reserveHostPort(proto network.IPProtocol, hostIP netip.Addr) (string, io.Closer, error) {
+	host := ""
+	if hostIP.IsValid() {
+		host = hostIP.String()
+	}
+	addr := net.JoinHostPort(host, "0")
+
+	switch proto {
+	case network.TCP:
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return "", nil, fmt.Errorf("listen: %w", err)
+		}
+
+		return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port), listener, nil
+	case network.UDP:
+		conn, err := net.ListenPacket("udp", addr)
+		if err != nil {
+			return "", nil, fmt.Errorf("listen packet: %w", err)
+		}
+
+		return strconv.Itoa(conn.LocalAddr().(*net.UDPAddr).Port), conn, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported protocol %s", proto)
+	}
+}
+
 // defaultHostConfigModifier provides a default modifier including the deprecated fields
 func defaultConfigModifier(req ContainerRequest) func(config *container.Config) {
 	return func(config *container.Config) {
@@ -6,8 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/netip"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -1002,3 +1004,90 @@ func Test_combineContainerHooks(t *testing.T) {
 		}
 	}
 }
+
+func TestReservePortBindings(t *testing.T) {
+	t.Run("ephemeral", func(t *testing.T) {
+		portMap := network.PortMap{
+			network.MustParsePort("80/tcp"):   {{HostPort: "0"}},
+			network.MustParsePort("53/udp"):   {{HostPort: ""}},
+			network.MustParsePort("8080/tcp"): {{HostIP: netip.MustParseAddr("127.0.0.1"), HostPort: "0"}},
+		}
+
+		reservation, err := reservePortBindings(portMap)
+		require.NoError(t, err)
+		defer reservation.release()
+
+		for p, bindings := range portMap {
+			require.Len(t, bindings, 1)
+			hostPort, err := strconv.Atoi(bindings[0].HostPort)
+			require.NoError(t, err, p)
+			require.Positive(t, hostPort, p)
+		}
+		require.Equal(t, netip.MustParseAddr("127.0.0.1"), portMap[network.MustParsePort("8080/tcp")][0].HostIP)
+	})
+
+	t.Run("reserved-until-released", func(t *testing.T) {
+		portMap := network.PortMap{
+			network.MustParsePort("80/tcp"): {{HostIP: netip.MustParseAddr("127.0.0.1"), HostPort: "0"}},
+			network.MustParsePort("53/udp"): {{HostIP: netip.MustParseAddr("127.0.0.1"), HostPort: "0"}},
+		}
+
+		reservation, err := reservePortBindings(portMap)
+		require.NoError(t, err)
+
+		tcpAddr := net.JoinHostPort("127.0.0.1", portMap[network.MustParsePort("80/tcp")][0].HostPort)
+		udpAddr := net.JoinHostPort("127.0.0.1", portMap[network.MustParsePort("53/udp")][0].HostPort)
+
+		_, err = net.Listen("tcp", tcpAddr)
+		require.Error(t, err)
+		_, err = net.ListenPacket("udp", udpAddr)
+		require.Error(t, err)
+
+		reservation.release()
+		reservation.release() // no-op
+
+		listener, err := net.Listen("tcp", tcpAddr)
+		require.NoError(t, err)
+		require.NoError(t, listener.Close())
+
+		conn, err := net.ListenPacket("udp", udpAddr)
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	})
+
+	t.Run("fixed", func(t *testing.T) {
+		portMap := network.PortMap{
+			network.MustParsePort("80/tcp"): {{HostPort: "8080"}},
+		}
+
+		reservation, err := reservePortBindings(portMap)
+		require.NoError(t, err)
+		require.Empty(t, reservation.closers)
+		require.Equal(t, network.PortMap{
+			network.MustParsePort("80/tcp"): {{HostPort: "8080"}},
+		}, portMap)
+	})
+
+	t.Run("copied", func(t *testing.T) {
+		bindings := []network.PortBinding{{HostPort: "0"}}
+		portMap := network.PortMap{
+			network.MustParsePort("80/tcp"): bindings,
+		}
+
+		reservation, err := reservePortBindings(portMap)
+		require.NoError(t, err)
+		defer reservation.release()
+
+		require.NotEqual(t, "0", portMap[network.MustParsePort("80/tcp")][0].HostPort)
+		require.Equal(t, "0", bindings[0].HostPort)
+	})
+
+	t.Run("unsupported-protocol", func(t *testing.T) {
+		portMap := network.PortMap{
+			network.MustParsePort("9000/sctp"): {{HostPort: "0"}},
+		}
+
+		_, err := reservePortBindings(portMap)
+		require.EqualError(t, err, "reserve host port for 9000/sctp: unsupported protocol sctp")
+	})
+}
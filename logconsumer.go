@@ -1,5 +1,7 @@
 package testcontainers
 
+import "time"
+
 // StdoutLog is the log type for STDOUT
 const StdoutLog = "STDOUT"
 
@@ -10,10 +12,12 @@ const StderrLog = "STDERR"
 
 // Log represents a message that was created by a process,
 // LogType is either "STDOUT" or "STDERR",
-// Content is the byte contents of the message itself
+// Content is the byte contents of the message itself,
+// Timestamp is the time the message was received by Docker, zero if unknown
 type Log struct {
-	LogType string
-	Content []byte
+	LogType   string
+	Content   []byte
+	Timestamp time.Time
 }
 
 // }
@@ -29,6 +33,22 @@ type LogConsumer interface {
 
 // }
 
+// lineLogConsumerInterface {
+
+// LineLogConsumer is a [LogConsumer] that can opt in to receive each line of the
+// logs as a separate [Log], including its trailing newline, instead of the chunks
+// of the logs as they are produced. The chunks of a line are buffered until the
+// line is complete, and a last line without a trailing newline is sent once the
+// logs stream ends.
+type LineLogConsumer interface {
+	LogConsumer
+
+	// AcceptLines returns true if the consumer receives complete lines.
+	AcceptLines() bool
+}
+
+// }
+
 // LogConsumerConfig is a configuration object for the producer/consumer pattern
 type LogConsumerConfig struct {
 	Opts      []LogProductionOption // options for the production of logs
@@ -0,0 +1,269 @@
+package testcontainers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JSONLog is a log line of a container parsed as a JSON object.
+type JSONLog struct {
+	// LogType is either "STDOUT" or "STDERR".
+	LogType string
+
+	// Timestamp is the time the line was received by Docker, zero if unknown.
+	Timestamp time.Time
+
+	// Fields are the fields of the JSON object.
+	Fields map[string]any
+
+	// Raw is the JSON line, without the trailing newline.
+	Raw []byte
+}
+
+// Field returns the value of the field, which can be nested using dots,
+// e.g. "request.method". It returns false if the field doesn't exist.
+func (l JSONLog) Field(name string) (any, bool) {
+	if v, ok := l.Fields[name]; ok {
+		return v, true
+	}
+
+	var v any = l.Fields
+	for part := range strings.SplitSeq(name, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if v, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// FieldString returns the value of the field as a string, or an empty string if
+// the field doesn't exist. See [JSONLog.Field] for the syntax of name.
+func (l JSONLog) FieldString(name string) string {
+	v, ok := l.Field(name)
+	if !ok || v == nil {
+		return ""
+	}
+
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(v)
+}
+
+// Decode unmarshals the JSON line into v, to get a typed record.
+func (l JSONLog) Decode(v any) error {
+	if err := json.Unmarshal(l.Raw, v); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return nil
+}
+
+// JSONLogPredicate reports whether a [JSONLog] matches.
+type JSONLogPredicate func(JSONLog) bool
+
+// JSONFieldEquals returns a predicate matching the logs whose field is equal to
+// value, once value is converted to JSON, so that numbers match regardless of
+// their Go type. See [JSONLog.Field] for the syntax of field.
+func JSONFieldEquals(field string, value any) JSONLogPredicate {
+	want, err := normalizeJSONValue(value)
+	if err != nil {
+		return func(JSONLog) bool { return false }
+	}
+
+	return func(l JSONLog) bool {
+		v, ok := l.Field(field)
+		return ok && reflect.DeepEqual(v, want)
+	}
+}
+
+// And returns a predicate matching the logs matched by p and all the others.
+func (p JSONLogPredicate) And(others ...JSONLogPredicate) JSONLogPredicate {
+	return func(l JSONLog) bool {
+		if !p(l) {
+			return false
+		}
+		for _, o := range others {
+			if !o(l) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a predicate matching the logs matched by p or any of the others.
+func (p JSONLogPredicate) Or(others ...JSONLogPredicate) JSONLogPredicate {
+	return func(l JSONLog) bool {
+		if p(l) {
+			return true
+		}
+		for _, o := range others {
+			if o(l) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a predicate matching the logs not matched by p.
+func (p JSONLogPredicate) Not() JSONLogPredicate {
+	return func(l JSONLog) bool {
+		return !p(l)
+	}
+}
+
+// normalizeJSONValue converts v to the value it would be decoded to from JSON.
+func normalizeJSONValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	var normalized any
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return normalized, nil
+}
+
+// JSONLogConsumer is a [LineLogConsumer] that parses the log lines of a container
+// as JSON objects, keeping them in memory to be queried. The lines that are not
+// JSON objects are kept apart, see [JSONLogConsumer.Unparsed].
+//
+// It's safe for concurrent use.
+type JSONLogConsumer struct {
+	mtx      sync.Mutex // protects the fields below
+	logs     []JSONLog
+	unparsed []Log
+	changed  chan struct{}
+}
+
+// NewJSONLogConsumer returns a new [JSONLogConsumer].
+func NewJSONLogConsumer() *JSONLogConsumer {
+	return &JSONLogConsumer{
+		changed: make(chan struct{}),
+	}
+}
+
+// AcceptLines implements [LineLogConsumer], so that each log is a complete line,
+// including the last line of the logs without a trailing newline.
+func (c *JSONLogConsumer) AcceptLines() bool {
+	return true
+}
+
+// Accept parses the lines of the log.
+func (c *JSONLogConsumer) Accept(l Log) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for line := range bytes.Lines(l.Content) {
+		c.add(l, bytes.TrimRight(line, "\r\n"))
+	}
+
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// add parses line and records it.
+func (c *JSONLogConsumer) add(l Log, line []byte) {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(line, &fields); err != nil || fields == nil {
+		c.unparsed = append(c.unparsed, Log{
+			LogType:   l.LogType,
+			Content:   bytes.Clone(line),
+			Timestamp: l.Timestamp,
+		})
+		return
+	}
+
+	c.logs = append(c.logs, JSONLog{
+		LogType:   l.LogType,
+		Timestamp: l.Timestamp,
+		Fields:    fields,
+		Raw:       bytes.Clone(line),
+	})
+}
+
+// Logs returns all the JSON logs received so far.
+func (c *JSONLogConsumer) Logs() []JSONLog {
+	return c.Filter(func(JSONLog) bool { return true })
+}
+
+// Unparsed returns the lines received so far that are not JSON objects.
+func (c *JSONLogConsumer) Unparsed() []Log {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return append([]Log(nil), c.unparsed...)
+}
+
+// Filter returns the JSON logs received so far matching the predicate.
+func (c *JSONLogConsumer) Filter(predicate JSONLogPredicate) []JSONLog {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var logs []JSONLog
+	for _, l := range c.logs {
+		if predicate(l) {
+			logs = append(logs, l)
+		}
+	}
+
+	return logs
+}
+
+// Find returns the JSON logs received so far whose field is equal to value.
+// See [JSONFieldEquals] for the comparison.
+func (c *JSONLogConsumer) Find(field string, value any) []JSONLog {
+	return c.Filter(JSONFieldEquals(field, value))
+}
+
+// Count returns the number of JSON logs received so far matching the predicate.
+func (c *JSONLogConsumer) Count(predicate JSONLogPredicate) int {
+	return len(c.Filter(predicate))
+}
+
+// WaitFor waits for a JSON log matching the predicate, including the ones
+// already received, returning the first one. It returns an error if ctx is
+// done before such a log is received.
+func (c *JSONLogConsumer) WaitFor(ctx context.Context, predicate JSONLogPredicate) (JSONLog, error) {
+	var next int
+	for {
+		c.mtx.Lock()
+		logs, changed := c.logs[next:], c.changed
+		next = len(c.logs)
+		c.mtx.Unlock()
+
+		for _, l := range logs {
+			if predicate(l) {
+				return l, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return JSONLog{}, fmt.Errorf("wait for JSON log: %w", context.Cause(ctx))
+		case <-changed:
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package testcontainers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestJSONLogConsumer(t *testing.T) {
+	c := NewJSONLogConsumer()
+
+	// The lines split across several messages are joined by the log production.
+	w := newLogConsumerWriter(StdoutLog, []LogConsumer{c})
+	_, err := w.Write([]byte(`{"level":"INFO","component":"auth","msg":"started"}` + "\n" + `{"level":"ERR`))
+	require.NoError(t, err)
+	c.Accept(Log{LogType: StderrLog, Content: []byte("not json\n")})
+	_, err = w.Write([]byte(`OR","component":"db","status":500,"request":{"method":"GET"}}` + "\r\n"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("\n" + `{"level":"INFO","component":"db"}`))
+	require.NoError(t, err)
+
+	// The last line without a trailing newline is parsed once the logs stream ends.
+	require.Len(t, c.Logs(), 2)
+	w.Flush()
+
+	require.Len(t, c.Logs(), 3)
+	require.Equal(t, []Log{{LogType: StderrLog, Content: []byte("not json")}}, c.Unparsed())
+
+	t.Run("find", func(t *testing.T) {
+		found := c.Find("level", "ERROR")
+		require.Len(t, found, 1)
+		require.Equal(t, "db", found[0].FieldString("component"))
+
+		require.Len(t, c.Find("status", 500), 1)
+		require.Len(t, c.Find("request.method", "GET"), 1)
+		require.Empty(t, c.Find("level", "WARN"))
+	})
+
+	t.Run("count", func(t *testing.T) {
+		require.Equal(t, 2, c.Count(JSONFieldEquals("level", "INFO")))
+		require.Zero(t, c.Count(JSONFieldEquals("level", "ERROR").And(JSONFieldEquals("component", "auth"))))
+		require.Equal(t, 2, c.Count(JSONFieldEquals("level", "ERROR").Or(JSONFieldEquals("component", "auth"))))
+		require.Equal(t, 1, c.Count(JSONFieldEquals("component", "db").Not()))
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		var record struct {
+			Level  string `json:"level"`
+			Status int    `json:"status"`
+		}
+		require.NoError(t, c.Find("level", "ERROR")[0].Decode(&record))
+		require.Equal(t, "ERROR", record.Level)
+		require.Equal(t, 500, record.Status)
+	})
+
+	t.Run("wait-for/received", func(t *testing.T) {
+		l, err := c.WaitFor(context.Background(), JSONFieldEquals("component", "auth"))
+		require.NoError(t, err)
+		require.Equal(t, "started", l.FieldString("msg"))
+	})
+
+	t.Run("wait-for/later", func(t *testing.T) {
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			c.Accept(Log{LogType: StdoutLog, Content: []byte(`{"level":"INFO","msg":"ready"}` + "\n")})
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		l, err := c.WaitFor(ctx, JSONFieldEquals("msg", "ready"))
+		require.NoError(t, err)
+		require.Equal(t, "INFO", l.FieldString("level"))
+	})
+
+	t.Run("wait-for/timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := c.WaitFor(ctx, JSONFieldEquals("msg", "never"))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestJSONLogConsumerContainer(t *testing.T) {
+	ctx := context.Background()
+	consumer := NewJSONLogConsumer()
+
+	ctr, err := Run(ctx, alpineImage,
+		WithCmd("sh", "-c", `echo '{"level":"INFO","msg":"starting"}'; echo 'plain text'; echo '{"level":"ERROR","component":"auth"}' >&2; sleep 30`),
+		WithLogConsumers(consumer),
+		WithWaitStrategy(wait.ForLog("plain text")),
+	)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	l, err := consumer.WaitFor(waitCtx, JSONFieldEquals("component", "auth"))
+	require.NoError(t, err)
+	require.Equal(t, StderrLog, l.LogType)
+	require.False(t, l.Timestamp.IsZero())
+
+	// The streams are not ordered relative to each other.
+	require.Eventually(t, func() bool {
+		return consumer.Count(JSONFieldEquals("level", "INFO")) == 1 && len(consumer.Unparsed()) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -750,3 +751,245 @@ func TestContainerLogDrainOnTerminate(t *testing.T) {
 		})
 	}
 }
+
+type recordingLogConsumer struct {
+	logs []Log
+}
+
+func (c *recordingLogConsumer) Accept(l Log) {
+	l.Content = bytes.Clone(l.Content)
+	c.logs = append(c.logs, l)
+}
+
+// recordingLineLogConsumer is a recordingLogConsumer receiving complete lines.
+type recordingLineLogConsumer struct {
+	recordingLogConsumer
+}
+
+func (c *recordingLineLogConsumer) AcceptLines() bool {
+	return true
+}
+
+func TestParseLogTimestamp(t *testing.T) {
+	t.Run("timestamped", func(t *testing.T) {
+		ts, content := parseLogTimestamp([]byte("2024-05-01T10:20:30.123456789Z hello world\n"))
+		require.Equal(t, time.Date(2024, 5, 1, 10, 20, 30, 123456789, time.UTC), ts)
+		require.Equal(t, "hello world\n", string(content))
+	})
+
+	t.Run("not-timestamped", func(t *testing.T) {
+		ts, content := parseLogTimestamp([]byte("hello world\n"))
+		require.True(t, ts.IsZero())
+		require.Equal(t, "hello world\n", string(content))
+	})
+
+	t.Run("no-space", func(t *testing.T) {
+		ts, content := parseLogTimestamp([]byte("hello\n"))
+		require.True(t, ts.IsZero())
+		require.Equal(t, "hello\n", string(content))
+	})
+}
+
+func TestTimestampedLogConsumerWriter(t *testing.T) {
+	consumer := &recordingLogConsumer{}
+	w := newTimestampedLogConsumerWriter(StderrLog, []LogConsumer{consumer})
+
+	p := []byte("2024-05-01T10:20:30Z first\n2024-05-01T10:20:31.5Z second\n")
+	n, err := w.Write(p)
+	require.NoError(t, err)
+	require.Equal(t, len(p), n)
+
+	require.Len(t, consumer.logs, 2)
+	require.Equal(t, Log{
+		LogType:   StderrLog,
+		Content:   []byte("first\n"),
+		Timestamp: time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC),
+	}, consumer.logs[0])
+	require.Equal(t, Log{
+		LogType:   StderrLog,
+		Content:   []byte("second\n"),
+		Timestamp: time.Date(2024, 5, 1, 10, 20, 31, 500000000, time.UTC),
+	}, consumer.logs[1])
+}
+
+func TestTimestampedLogConsumerWriter_partialLines(t *testing.T) {
+	raw := &recordingLogConsumer{}
+	consumer := &recordingLineLogConsumer{}
+	w := newTimestampedLogConsumerWriter(StdoutLog, []LogConsumer{raw, consumer})
+
+	// A long line is split by Docker in several frames, each with its timestamp.
+	for _, frame := range []string{
+		"2024-05-01T10:20:30Z begin ",
+		"2024-05-01T10:20:31Z middle ",
+		"2024-05-01T10:20:32Z end\n2024-05-01T10:20:33Z next\n",
+		"2024-05-01T10:20:34Z unterminated",
+	} {
+		_, err := w.Write([]byte(frame))
+		require.NoError(t, err)
+	}
+
+	// The chunks are sent as they are written to the consumers not opting in to lines.
+	require.Len(t, raw.logs, 5)
+	require.Equal(t, Log{
+		LogType:   StdoutLog,
+		Content:   []byte("begin "),
+		Timestamp: time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC),
+	}, raw.logs[0])
+	require.Equal(t, "unterminated", string(raw.logs[4].Content))
+
+	require.Len(t, consumer.logs, 2)
+	require.Equal(t, Log{
+		LogType:   StdoutLog,
+		Content:   []byte("begin middle end\n"),
+		Timestamp: time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC),
+	}, consumer.logs[0])
+	require.Equal(t, "next\n", string(consumer.logs[1].Content))
+
+	// The unterminated line is sent once the stream ends.
+	w.Flush()
+	require.Len(t, consumer.logs, 3)
+	require.Equal(t, Log{
+		LogType:   StdoutLog,
+		Content:   []byte("unterminated"),
+		Timestamp: time.Date(2024, 5, 1, 10, 20, 34, 0, time.UTC),
+	}, consumer.logs[2])
+
+	w.Flush()
+	require.Len(t, consumer.logs, 3)
+	require.Len(t, raw.logs, 5)
+}
+
+func TestLogConsumerWriter_lines(t *testing.T) {
+	raw := &recordingLogConsumer{}
+	consumer := &recordingLineLogConsumer{}
+	w := newLogConsumerWriter(StdoutLog, []LogConsumer{raw, consumer})
+
+	for _, chunk := range []string{"> ", "first\nsec", "ond\n> "} {
+		_, err := w.Write([]byte(chunk))
+		require.NoError(t, err)
+	}
+
+	// The prompt is sent to the consumers as soon as it's written.
+	require.Len(t, raw.logs, 3)
+	require.Equal(t, "> ", string(raw.logs[0].Content))
+	require.Equal(t, "ond\n> ", string(raw.logs[2].Content))
+
+	require.Len(t, consumer.logs, 2)
+	require.Equal(t, "> first\n", string(consumer.logs[0].Content))
+	require.Equal(t, "second\n", string(consumer.logs[1].Content))
+
+	w.Flush()
+	require.Len(t, consumer.logs, 3)
+	require.Equal(t, "> ", string(consumer.logs[2].Content))
+}
+
+func TestLogTimestampTracker(t *testing.T) {
+	tracker := &logTimestampTracker{}
+
+	// Without any log, the logs are requested from now.
+	before := time.Now().Unix()
+	sec, _, ok := strings.Cut(tracker.since(), ".")
+	require.True(t, ok)
+	require.GreaterOrEqual(t, sec, strconv.FormatInt(before, 10))
+
+	_, err := tracker.Write([]byte("2024-05-01T10:20:30Z first\n2024-05-01T10:20:31.5Z second\n"))
+	require.NoError(t, err)
+
+	// The logs are requested right after the last one received.
+	require.Equal(t, fmt.Sprintf("%d.500000001", time.Date(2024, 5, 1, 10, 20, 31, 0, time.UTC).Unix()), tracker.since())
+}
+
+func TestContainerLogsWithOptions(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := Run(ctx, alpineImage,
+		WithCmd("sh", "-c", "echo 'one' && echo 'two' && echo 'three' >&2"),
+		WithWaitStrategy(wait.ForExit()),
+	)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	t.Run("tail", func(t *testing.T) {
+		r, err := ctr.LogsWithOptions(ctx, LogsOptions{Tail: 2})
+		require.NoError(t, err)
+		defer r.Close()
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "two\nthree\n", string(b))
+	})
+
+	t.Run("timestamps", func(t *testing.T) {
+		r, err := ctr.LogsWithOptions(ctx, LogsOptions{Timestamps: true})
+		require.NoError(t, err)
+		defer r.Close()
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+		require.Len(t, lines, 3)
+		for _, line := range lines {
+			ts, _ := parseLogTimestamp([]byte(line))
+			require.False(t, ts.IsZero(), line)
+		}
+	})
+
+	t.Run("until", func(t *testing.T) {
+		r, err := ctr.LogsWithOptions(ctx, LogsOptions{Until: time.Unix(1, 0)})
+		require.NoError(t, err)
+		defer r.Close()
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Empty(t, b)
+	})
+}
+
+func TestContainerLogConsumerTty(t *testing.T) {
+	ctx := context.Background()
+	consumer := &countingLogConsumer{}
+	recording := &syncRecordingLogConsumer{}
+
+	ctr, err := Run(ctx, alpineImage,
+		WithCmd("sh", "-c", "echo 'abcdefghi' && echo 'foo'"),
+		WithConfigModifier(func(ctr *container.Config) {
+			ctr.Tty = true
+		}),
+		WithLogConsumerConfig(&LogConsumerConfig{
+			Consumers: []LogConsumer{consumer, recording},
+		}),
+		WithWaitStrategy(wait.ForExit()),
+	)
+	CleanupContainer(t, ctr)
+	require.NoError(t, err)
+	require.NoError(t, ctr.Stop(ctx, nil))
+
+	logs := recording.Logs()
+	require.Len(t, logs, 2)
+	for _, l := range logs {
+		// The output of a TTY is never multiplexed, so it's all stdout,
+		// without any stream header.
+		require.Equal(t, StdoutLog, l.LogType)
+		require.False(t, l.Timestamp.IsZero())
+	}
+	require.Equal(t, "abcdefghi\r\n", string(logs[0].Content))
+	require.Equal(t, "foo\r\n", string(logs[1].Content))
+}
+
+type syncRecordingLogConsumer struct {
+	mtx      sync.Mutex
+	recorder recordingLogConsumer
+}
+
+func (c *syncRecordingLogConsumer) Accept(l Log) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.recorder.Accept(l)
+}
+
+func (c *syncRecordingLogConsumer) Logs() []Log {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return append([]Log(nil), c.recorder.logs...)
+}
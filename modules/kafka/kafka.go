@@ -17,16 +17,26 @@ import (
 const publicPort = "9093/tcp"
 const (
 	starterScript = "/usr/sbin/testcontainers_start.sh"
+	// serverLog is the broker log, written in addition to the container logs.
+	serverLog = "/var/log/kafka/server.log"
 
 	// starterScript {
 	starterScriptContent = `#!/bin/bash
 source /etc/confluent/docker/bash-config
-export KAFKA_ADVERTISED_LISTENERS=%s,BROKER://%s:9092
+export KAFKA_ADVERTISED_LISTENERS=%[1]s,BROKER://%[2]s:9092
 echo Starting Kafka KRaft mode
 sed -i '/KAFKA_ZOOKEEPER_CONNECT/d' /etc/confluent/docker/configure
 echo 'kafka-storage format --ignore-formatted -t "$(kafka-storage random-uuid)" -c /etc/kafka/kafka.properties' >> /etc/confluent/docker/configure
 echo '' > /etc/confluent/docker/ensure
 /etc/confluent/docker/configure
+mkdir -p "$(dirname %[3]s)" || true
+cat >> /etc/kafka/log4j.properties <<EOF
+log4j.rootLogger=${KAFKA_LOG4J_ROOT_LOGLEVEL:-INFO}, stdout, serverAppender
+log4j.appender.serverAppender=org.apache.log4j.FileAppender
+log4j.appender.serverAppender.File=%[3]s
+log4j.appender.serverAppender.layout=org.apache.log4j.PatternLayout
+log4j.appender.serverAppender.layout.ConversionPattern=[%%d] %%p %%m (%%c)%%n
+EOF
 /etc/confluent/docker/launch`
 	// }
 )
@@ -49,7 +59,7 @@ func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustom
 		return nil, err
 	}
 
-	moduleOpts := make([]testcontainers.ContainerCustomizer, 0, 5+len(opts)+1)
+	moduleOpts := make([]testcontainers.ContainerCustomizer, 0, 6+len(opts)+1)
 	moduleOpts = append(moduleOpts,
 		testcontainers.WithExposedPorts(string(publicPort)),
 		testcontainers.WithEnv(map[string]string{
@@ -70,6 +80,8 @@ func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustom
 			"KAFKA_CONTROLLER_LISTENER_NAMES":                "CONTROLLER",
 			// }
 		}),
+		// the broker log, the broker configuration rendered from the environment, and the starter script.
+		testcontainers.WithFailureArtifacts("", serverLog, "/etc/kafka/kafka.properties", starterScript),
 		testcontainers.WithEntrypoint("sh"),
 		// this CMD will wait for the starter script to be copied into the container and then execute it
 		testcontainers.WithCmd("-c", "while [ ! -f "+starterScript+" ]; do sleep 0.1; done; bash "+starterScript),
@@ -140,7 +152,7 @@ func copyStarterScript(ctx context.Context, c testcontainers.Container) error {
 
 	hostname := inspect.Config.Hostname
 
-	scriptContent := fmt.Sprintf(starterScriptContent, endpoint, hostname)
+	scriptContent := fmt.Sprintf(starterScriptContent, endpoint, hostname, serverLog)
 
 	if err := c.CopyToContainer(ctx, []byte(scriptContent), starterScript, 0o755); err != nil {
 		return fmt.Errorf("copy to container: %w", err)
@@ -64,6 +64,10 @@ func TestKafka(t *testing.T) {
 
 	require.Truef(t, strings.EqualFold(string(consumer.message.Key), "key"), "expected key to be %s, got %s", "key", string(consumer.message.Key))
 	require.Truef(t, strings.EqualFold(string(consumer.message.Value), "value"), "expected value to be %s, got %s", "value", string(consumer.message.Value))
+
+	// the broker log is collected as a failure artifact.
+	serverLog := testcontainers.RequireContainerExec(ctx, t, kafkaContainer, []string{"cat", "/var/log/kafka/server.log"})
+	require.Contains(t, serverLog, "Transitioning from RECOVERY to RUNNING")
 }
 
 func TestKafka_invalidVersion(t *testing.T) {
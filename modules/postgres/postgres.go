@@ -9,6 +9,7 @@ import (
 	"io"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/log"
@@ -152,7 +153,7 @@ func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustom
 		}
 	}
 
-	moduleOpts := make([]testcontainers.ContainerCustomizer, 0, 3+len(opts))
+	moduleOpts := make([]testcontainers.ContainerCustomizer, 0, 5+len(opts))
 	moduleOpts = append(moduleOpts,
 		testcontainers.WithEnv(map[string]string{
 			"POSTGRES_USER":     defaultUser,
@@ -161,6 +162,13 @@ func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustom
 		}),
 		testcontainers.WithExposedPorts("5432/tcp"),
 		testcontainers.WithCmd("postgres", "-c", "fsync=off"),
+		// the log files of the logging collector, when it's enabled in the configuration.
+		testcontainers.WithFailureArtifacts("", "/var/lib/postgresql/data/log"),
+		// defaultHealthCheck {
+		// the server started by the entrypoint to run the init scripts doesn't listen on TCP,
+		// so the container is only healthy once the final server is ready.
+		testcontainers.WithHealthCheckShell(`pg_isready -h 127.0.0.1 -U "$POSTGRES_USER" -d "$POSTGRES_DB"`, time.Second, 5*time.Second, 3, time.Minute),
+		// }
 	)
 
 	moduleOpts = append(moduleOpts, opts...)
@@ -187,6 +187,45 @@ func TestContainerWithWaitForSQL(t *testing.T) {
 	})
 }
 
+func TestDefaultHealthCheck(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("default", func(t *testing.T) {
+		ctr, err := postgres.Run(ctx,
+			"postgres:16-alpine",
+			postgres.WithDatabase(dbname),
+			postgres.WithUsername(user),
+			postgres.WithPassword(password),
+			testcontainers.WithWaitStrategy(wait.ForHealthCheck().WithStartupTimeout(time.Minute)),
+		)
+		testcontainers.CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		connStr, err := ctr.ConnectionString(ctx, "sslmode=disable")
+		require.NoError(t, err)
+
+		db, err := sql.Open("postgres", connStr)
+		require.NoError(t, err)
+		defer db.Close()
+
+		require.NoError(t, db.PingContext(ctx))
+	})
+
+	t.Run("override", func(t *testing.T) {
+		ctr, err := postgres.Run(ctx,
+			"postgres:16-alpine",
+			testcontainers.WithHealthCheckShell("test -f /tmp/ready", time.Second, time.Second, 3, 0),
+			postgres.BasicWaitStrategies(),
+		)
+		testcontainers.CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		inspect, err := ctr.Inspect(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"CMD-SHELL", "test -f /tmp/ready"}, inspect.Config.Healthcheck.Test)
+	})
+}
+
 func TestWithConfigFile(t *testing.T) {
 	ctx := context.Background()
 
@@ -6,7 +6,6 @@ toolchain go1.25.9
 
 require (
 	github.com/google/uuid v1.6.0
-	github.com/mdelapenya/tlscert v0.2.0
 	github.com/redis/go-redis/v9 v9.7.3
 	github.com/stretchr/testify v1.11.1
 	github.com/testcontainers/testcontainers-go v0.43.0
@@ -2,17 +2,16 @@ package redis
 
 import (
 	"crypto/tls"
-	"fmt"
-	"net"
+	"errors"
 	"strconv"
 
-	"github.com/mdelapenya/tlscert"
-
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/tlscert"
 )
 
 type options struct {
 	tlsEnabled bool
+	tlsCA      *tlscert.CA
 	tlsConfig  *tls.Config
 }
 
@@ -37,6 +36,20 @@ func WithTLS() Option {
 	}
 }
 
+// WithTLSFrom enables TLS like [WithTLS], issuing the certificates from the given CA
+// instead of a new one, so that it can be shared with the other containers and clients.
+func WithTLSFrom(ca *tlscert.CA) Option {
+	return func(o *options) error {
+		if ca == nil {
+			return errors.New("nil CA")
+		}
+
+		o.tlsEnabled = true
+		o.tlsCA = ca
+		return nil
+	}
+}
+
 // WithConfigFile sets the config file to be used for the redis container, and sets the command to run the redis server
 // using the passed config file
 func WithConfigFile(configFile string) testcontainers.CustomizeRequestOption {
@@ -80,48 +93,3 @@ func WithSnapshotting(seconds int, changedKeys int) testcontainers.CustomizeRequ
 
 	return testcontainers.WithCmdArgs("--save", strconv.Itoa(seconds), strconv.Itoa(changedKeys))
 }
-
-// createTLSCerts creates a CA certificate, a client certificate and a Redis certificate.
-func createTLSCerts() (caCert *tlscert.Certificate, clientCert *tlscert.Certificate, redisCert *tlscert.Certificate, err error) {
-	// ips is the extra list of IPs to include in the certificates.
-	// It's used to allow the client and Redis certificates to be used in the same host
-	// when the tests are run using a remote docker daemon.
-	ips := []net.IP{net.ParseIP("127.0.0.1")}
-
-	// Generate CA certificate
-	caCert, err = tlscert.SelfSignedFromRequestE(tlscert.Request{
-		Host:              "localhost",
-		IPAddresses:       ips,
-		Name:              "ca",
-		SubjectCommonName: "ca",
-		IsCA:              true,
-	})
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("generate CA certificate: %w", err)
-	}
-
-	// Generate client certificate
-	clientCert, err = tlscert.SelfSignedFromRequestE(tlscert.Request{
-		Host:              "localhost",
-		Name:              "Redis Client",
-		SubjectCommonName: "localhost",
-		IPAddresses:       ips,
-		Parent:            caCert,
-	})
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("generate client certificate: %w", err)
-	}
-
-	// Generate Redis certificate
-	redisCert, err = tlscert.SelfSignedFromRequestE(tlscert.Request{
-		Host:        "localhost",
-		IPAddresses: ips,
-		Name:        "Redis Server",
-		Parent:      caCert,
-	})
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("generate Redis certificate: %w", err)
-	}
-
-	return caCert, clientCert, redisCert, nil
-}
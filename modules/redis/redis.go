@@ -1,7 +1,6 @@
 package redis
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
@@ -9,6 +8,7 @@ import (
 	"time"
 
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/tlscert"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
@@ -75,11 +75,24 @@ func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustom
 	}
 
 	if settings.tlsEnabled {
-		// Generate TLS certificates in the fly and add them to the container before it starts.
-		// Update the CMD to use the TLS certificates.
-		caCert, clientCert, serverCert, err := createTLSCerts()
+		// Issue the TLS certificates from the CA, creating it if not shared,
+		// and add them to the container before it starts.
+		ca := settings.tlsCA
+		if ca == nil {
+			var err error
+			if ca, err = tlscert.NewCA(); err != nil {
+				return nil, fmt.Errorf("create tls ca: %w", err)
+			}
+		}
+
+		clientCert, err := ca.Issue("Redis Client")
 		if err != nil {
-			return nil, fmt.Errorf("create tls certs: %w", err)
+			return nil, fmt.Errorf("issue client certificate: %w", err)
+		}
+
+		clientTLS, err := clientCert.TLSCertificate()
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
 		}
 
 		// Update the CMD to use the TLS certificates.
@@ -87,39 +100,20 @@ func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustom
 			"--tls-port", strings.Replace(redisPort, "/tcp", "", 1),
 			// Disable the default port, as described in https://redis.io/docs/latest/operate/oss_and_stack/management/security/encryption/#running-manually
 			"--port", "0",
-			"--tls-cert-file", "/tls/server.crt",
-			"--tls-key-file", "/tls/server.key",
-			"--tls-ca-cert-file", "/tls/ca.crt",
+			"--tls-cert-file", tlscert.CertPath,
+			"--tls-key-file", tlscert.KeyPath,
+			"--tls-ca-cert-file", tlscert.CACertPath,
 			"--tls-auth-clients", "yes",
 		}
 
 		moduleOpts = append(moduleOpts,
 			testcontainers.WithCmdArgs(cmds...),
-			testcontainers.WithFiles(
-				testcontainers.ContainerFile{
-					Reader:            bytes.NewReader(caCert.Bytes),
-					ContainerFilePath: "/tls/ca.crt",
-					FileMode:          0o644,
-				},
-				testcontainers.ContainerFile{
-					Reader:            bytes.NewReader(serverCert.Bytes),
-					ContainerFilePath: "/tls/server.crt",
-					FileMode:          0o644,
-				},
-				testcontainers.ContainerFile{
-					Reader:            bytes.NewReader(serverCert.KeyBytes),
-					ContainerFilePath: "/tls/server.key",
-					FileMode:          0o644,
-				},
-			),
+			// The server drops the root privileges for the redis user, whose group depends on the image.
+			tlscert.WithTLSFrom(ca, tlscert.WithKeyMode(0o644)),
 		)
 
-		settings.tlsConfig = &tls.Config{
-			MinVersion:   tls.VersionTLS12,
-			RootCAs:      caCert.TLSConfig().RootCAs,
-			Certificates: clientCert.TLSConfig().Certificates,
-			ServerName:   "localhost", // Match the server cert's common name
-		}
+		settings.tlsConfig = ca.TLSConfig()
+		settings.tlsConfig.Certificates = []tls.Certificate{clientTLS}
 	}
 
 	// Append the customizers passed to the Run function.
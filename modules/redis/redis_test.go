@@ -2,7 +2,9 @@ package redis_test
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"testing"
 	"time"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/testcontainers/testcontainers-go"
 	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/testcontainers/testcontainers-go/tlscert"
 )
 
 func TestIntegrationSetGet(t *testing.T) {
@@ -88,6 +91,36 @@ func TestRedisWithTLS(t *testing.T) {
 
 		assertSetsGets(t, ctx, redisContainer, 1)
 	})
+
+	t.Run("shared-ca", func(t *testing.T) {
+		// withTLSFrom {
+		ca, err := tlscert.NewCA()
+		require.NoError(t, err)
+
+		redisContainer, err := tcredis.Run(ctx, "redis:7", tcredis.WithTLSFrom(ca))
+		// }
+		testcontainers.CleanupContainer(t, redisContainer)
+		require.NoError(t, err)
+
+		assertSetsGets(t, ctx, redisContainer, 1)
+
+		// The server certificate is issued by the shared CA.
+		uri, err := redisContainer.ConnectionString(ctx)
+		require.NoError(t, err)
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+
+		config := ca.TLSConfig()
+		config.Certificates = redisContainer.TLSConfig().Certificates
+		conn, err := tls.Dial("tcp", u.Host, config)
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	})
+
+	t.Run("nil-ca", func(t *testing.T) {
+		_, err := tcredis.Run(ctx, "redis:7", tcredis.WithTLSFrom(nil))
+		require.EqualError(t, err, "nil CA")
+	})
 }
 
 func assertSetsGets(t *testing.T, ctx context.Context, redisContainer *tcredis.RedisContainer, keyCount int) {
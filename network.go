@@ -40,6 +40,7 @@ type NetworkRequest struct {
 	CheckDuplicate bool // Deprecated: CheckDuplicate is deprecated since API v1.44, but it defaults to true when sent by the client package to older daemons.
 	Internal       bool
 	EnableIPv6     *bool
+	EnableIPv4     *bool
 	Name           string
 	Labels         map[string]string
 	Attachable     bool
@@ -37,6 +37,7 @@ func New(ctx context.Context, opts ...NetworkCustomizer) (*testcontainers.Docker
 		Driver:     nc.Driver,
 		Internal:   nc.Internal,
 		EnableIPv6: nc.EnableIPv6,
+		EnableIPv4: nc.EnableIPv4,
 		Name:       uuid.NewString(),
 		Labels:     nc.Labels,
 		Attachable: nc.Attachable,
@@ -107,6 +108,16 @@ func WithEnableIPv6() CustomizeNetworkOption {
 	}
 }
 
+// WithDisableIPv4 allows to create an IPv6-only network, together with [WithEnableIPv6].
+// Please use this option if and only if the Docker daemon supports it, from API v1.47.
+func WithDisableIPv4() CustomizeNetworkOption {
+	return func(original *client.NetworkCreateOptions) error {
+		enableIPv4 := false
+		original.EnableIPv4 = &enableIPv4
+		return nil
+	}
+}
+
 // WithInternal allows to set the network as internal.
 func WithInternal() CustomizeNetworkOption {
 	return func(original *client.NetworkCreateOptions) error {
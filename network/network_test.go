@@ -2,8 +2,11 @@ package network_test
 
 import (
 	"context"
+	"net"
+	"net/http"
 	"net/netip"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
@@ -129,6 +132,53 @@ func TestContainerIPs(t *testing.T) {
 	require.Len(t, ips, 2)
 }
 
+func TestContainerIPv6(t *testing.T) {
+	// The IPv6 loopback address is needed to reach the container through it.
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback address not available: %s", err)
+	}
+	require.NoError(t, listener.Close())
+
+	ctx := context.Background()
+
+	nw, err := network.New(ctx, network.WithEnableIPv6())
+	testcontainers.CleanupNetwork(t, nw)
+	if err != nil {
+		t.Skipf("IPv6 networks not supported by the Docker daemon: %s", err)
+	}
+
+	t.Setenv("TESTCONTAINERS_HOST_OVERRIDE", "::1")
+
+	nginx, err := testcontainers.Run(ctx, nginxAlpineImage,
+		testcontainers.WithExposedPorts(nginxDefaultPort),
+		network.WithNetwork([]string{"nginx"}, nw),
+		testcontainers.WithWaitStrategy(wait.ForHTTP("/").WithPort(nginxDefaultPort)),
+	)
+	testcontainers.CleanupContainer(t, nginx)
+	require.NoError(t, err)
+
+	ips, err := nginx.ContainerIPs(ctx)
+	require.NoError(t, err)
+	require.True(t, slices.ContainsFunc(ips, func(ip string) bool {
+		addr, err := netip.ParseAddr(ip)
+		return err == nil && addr.Is6()
+	}), "no IPv6 address in %v", ips)
+
+	port, err := nginx.MappedPort(ctx, nginxDefaultPort)
+	require.NoError(t, err)
+	require.False(t, port.IsZero())
+
+	endpoint, err := nginx.PortEndpoint(ctx, nginxDefaultPort, "http")
+	require.NoError(t, err)
+	require.Equal(t, "http://[::1]:"+port.Port(), endpoint)
+
+	resp, err := http.Get(endpoint)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
 func TestContainerWithReaperNetwork(t *testing.T) {
 	if core.IsWindows() {
 		t.Skip("Skip for Windows. See https://stackoverflow.com/questions/43784916/docker-for-windows-networking-container-with-multiple-network-interfaces")
@@ -105,6 +105,58 @@ func WithHostPortAccess(ports ...int) CustomizeRequestOption {
 	}
 }
 
+// WithStablePorts pins the host ports mapped to the exposed ports of the container,
+// so that [DockerContainer.Restart], or stopping and starting the container, keeps
+// its endpoints identical, while Docker assigns new random host ports otherwise.
+//
+// As Docker doesn't allow changing the port bindings of an existing container, the
+// host ports are picked among the free ports of the host before the container is
+// created, instead of by Docker when the container starts. They stay bound until
+// right before the container starts, so that no other process takes them, and if
+// one is still taken in that short time, the container is created again.
+//
+// The free ports can only be picked when the daemon runs on the host of the tests,
+// so creating the container fails when the daemon is remote, or when the tests run
+// in a container.
+func WithStablePorts() CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.StablePorts = true
+		return nil
+	}
+}
+
+// WithHostSocketAccess forwards the host Unix socket at hostPath to the
+// container, at the absolute containerPath, over the SSH tunnel used by
+// [WithHostPortAccess]. Unlike a bind mount, it works with remote Docker hosts.
+func WithHostSocketAccess(hostPath string, containerPath string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if hostPath == "" {
+			return errors.New("host socket path must be provided")
+		}
+
+		if !path.IsAbs(containerPath) {
+			return fmt.Errorf("container socket path %q must be absolute", containerPath)
+		}
+
+		if req.HostAccessSockets == nil {
+			req.HostAccessSockets = make(map[string]string)
+		}
+
+		req.HostAccessSockets[path.Clean(containerPath)] = hostPath
+		return nil
+	}
+}
+
+// WithHostAccessMode sets how the container reaches the host ports exposed with
+// [WithHostPortAccess] or [ExposeHostPort]. See [HostAccessGateway] to reach
+// the host without the SSH tunnel.
+func WithHostAccessMode(mode HostAccessMode) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.HostAccessMode = mode
+		return nil
+	}
+}
+
 // WithName will set the name of the container.
 func WithName(containerName string) CustomizeRequestOption {
 	return func(req *GenericContainerRequest) error {
@@ -399,6 +451,48 @@ func WithAdditionalWaitStrategyAndDeadline(deadline time.Duration, strategies ..
 	}
 }
 
+// WithHealthCheck sets the healthcheck of the container, overriding the one of the image,
+// to be used with [wait.ForHealthCheck]. The cmd is in the Docker format: ["CMD", args...]
+// to run a command, ["CMD-SHELL", command] to run a command with the shell of the
+// container, or ["NONE"] to disable the healthcheck.
+// Zero durations and retries use the defaults of the container runtime.
+//
+// Modules can declare a default healthcheck with it, which the users of the
+// module override with their own.
+func WithHealthCheck(cmd []string, interval time.Duration, timeout time.Duration, retries int, startPeriod time.Duration) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if len(cmd) == 0 {
+			return errors.New("healthcheck command is empty")
+		}
+
+		req.HealthCheck = &container.HealthConfig{
+			Test:        cmd,
+			Interval:    interval,
+			Timeout:     timeout,
+			Retries:     retries,
+			StartPeriod: startPeriod,
+		}
+
+		return nil
+	}
+}
+
+// WithHealthCheckShell sets the healthcheck of the container to a command run
+// with the shell of the container. See [WithHealthCheck].
+func WithHealthCheckShell(command string, interval time.Duration, timeout time.Duration, retries int, startPeriod time.Duration) CustomizeRequestOption {
+	return WithHealthCheck([]string{"CMD-SHELL", command}, interval, timeout, retries, startPeriod)
+}
+
+// WithHealthCheckExec sets the healthcheck of the container to a command run
+// without a shell, the first element being the executable. See [WithHealthCheck].
+func WithHealthCheckExec(cmd []string, interval time.Duration, timeout time.Duration, retries int, startPeriod time.Duration) CustomizeRequestOption {
+	if len(cmd) == 0 {
+		return WithHealthCheck(nil, interval, timeout, retries, startPeriod)
+	}
+
+	return WithHealthCheck(append([]string{"CMD"}, cmd...), interval, timeout, retries, startPeriod)
+}
+
 // WithImageMount mounts an image to a container, passing the source image name,
 // the relative subpath to mount in that image, and the mount point in the target container.
 // This option validates that the subpath is a relative path, raising an error otherwise.
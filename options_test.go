@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"github.com/moby/moby/client/pkg/versions"
 	"github.com/stretchr/testify/require"
@@ -904,3 +905,115 @@ func TestWithProvider(t *testing.T) {
 		require.Equal(t, testcontainers.ProviderPodman, req.ProviderType)
 	})
 }
+
+func TestWithHealthCheck(t *testing.T) {
+	testHealthCheck := func(t *testing.T, opt testcontainers.CustomizeRequestOption, expected *container.HealthConfig) {
+		t.Helper()
+
+		req := &testcontainers.GenericContainerRequest{}
+		require.NoError(t, opt.Customize(req))
+		require.Equal(t, expected, req.HealthCheck)
+	}
+
+	t.Run("raw", func(t *testing.T) {
+		testHealthCheck(t,
+			testcontainers.WithHealthCheck([]string{"CMD", "pg_isready"}, time.Second, 2*time.Second, 3, 4*time.Second),
+			&container.HealthConfig{
+				Test:        []string{"CMD", "pg_isready"},
+				Interval:    time.Second,
+				Timeout:     2 * time.Second,
+				Retries:     3,
+				StartPeriod: 4 * time.Second,
+			},
+		)
+	})
+
+	t.Run("shell", func(t *testing.T) {
+		testHealthCheck(t,
+			testcontainers.WithHealthCheckShell("pg_isready || exit 1", time.Second, 0, 0, 0),
+			&container.HealthConfig{
+				Test:     []string{"CMD-SHELL", "pg_isready || exit 1"},
+				Interval: time.Second,
+			},
+		)
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		testHealthCheck(t,
+			testcontainers.WithHealthCheckExec([]string{"pg_isready", "-U", "postgres"}, time.Second, 0, 0, 0),
+			&container.HealthConfig{
+				Test:     []string{"CMD", "pg_isready", "-U", "postgres"},
+				Interval: time.Second,
+			},
+		)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+
+		// The default healthcheck of a module is overridden by the one of the user.
+		require.NoError(t, testcontainers.WithHealthCheckShell("module", time.Second, 0, 0, 0).Customize(req))
+		require.NoError(t, testcontainers.WithHealthCheckExec([]string{"user"}, time.Second, 0, 0, 0).Customize(req))
+		require.Equal(t, []string{"CMD", "user"}, req.HealthCheck.Test)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+		require.Error(t, testcontainers.WithHealthCheck(nil, time.Second, 0, 0, 0).Customize(req))
+		require.Error(t, testcontainers.WithHealthCheckExec(nil, time.Second, 0, 0, 0).Customize(req))
+	})
+
+	t.Run("healthy", func(t *testing.T) {
+		ctx := context.Background()
+		c, err := testcontainers.Run(ctx, "alpine",
+			testcontainers.WithEntrypoint("tail", "-f", "/dev/null"),
+			testcontainers.WithHealthCheckShell("test -f /tmp/ready", 100*time.Millisecond, time.Second, 0, 0),
+			testcontainers.WithAfterReadyCommand(testcontainers.NewRawCommand([]string{"touch", "/tmp/ready"})),
+			testcontainers.WithWaitStrategy(wait.ForExec([]string{"true"})),
+		)
+		testcontainers.CleanupContainer(t, c)
+		require.NoError(t, err)
+
+		err = wait.ForHealthCheck().WithStartupTimeout(10*time.Second).WaitUntilReady(ctx, c)
+		require.NoError(t, err)
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+		ctx := context.Background()
+		c, err := testcontainers.Run(ctx, "alpine",
+			testcontainers.WithEntrypoint("tail", "-f", "/dev/null"),
+			testcontainers.WithHealthCheckShell("echo not ready yet; exit 1", 100*time.Millisecond, time.Second, 1, 0),
+			testcontainers.WithWaitStrategy(wait.ForHealthCheck().WithStartupTimeout(2*time.Second)),
+		)
+		testcontainers.CleanupContainer(t, c)
+		require.ErrorContains(t, err, `last healthcheck exit code 1, output "not ready yet\n"`)
+
+		var waitErr *wait.WaitError
+		require.ErrorAs(t, err, &waitErr)
+		require.Contains(t, waitErr.Summary(), "health: unhealthy")
+	})
+}
+
+func TestWithHostSocketAccess(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+		require.NoError(t, testcontainers.WithHostSocketAccess("/tmp/agent.sock", "/run/agent.sock").Customize(req))
+		require.NoError(t, testcontainers.WithHostSocketAccess("/tmp/grpc.sock", "/var/run/grpc/../grpc.sock").Customize(req))
+		require.Equal(t, map[string]string{
+			"/run/agent.sock":    "/tmp/agent.sock",
+			"/var/run/grpc.sock": "/tmp/grpc.sock",
+		}, req.HostAccessSockets)
+	})
+
+	t.Run("empty-host-path", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+		err := testcontainers.WithHostSocketAccess("", "/run/agent.sock").Customize(req)
+		require.EqualError(t, err, "host socket path must be provided")
+	})
+
+	t.Run("relative-container-path", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+		err := testcontainers.WithHostSocketAccess("/tmp/agent.sock", "agent.sock").Customize(req)
+		require.EqualError(t, err, `container socket path "agent.sock" must be absolute`)
+	})
+}
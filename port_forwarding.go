@@ -1,19 +1,35 @@
 package testcontainers
 
 import (
+	"archive/tar"
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
+	"net/url"
 	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/moby/moby/api/types/container"
+	dockernetwork "github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+	"github.com/moby/moby/client/pkg/security"
+	"github.com/moby/moby/client/pkg/versions"
 	"golang.org/x/crypto/ssh"
 
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/internal/core"
 	"github.com/testcontainers/testcontainers-go/internal/core/network"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
@@ -28,21 +44,130 @@ const (
 	HostInternal string = "host.testcontainers.internal"
 	user         string = "root"
 	sshPort             = "22/tcp"
+
+	// hostSocketsDir is the directory of the volume holding the forwarded host
+	// sockets, in the SSHD container and the container.
+	hostSocketsDir = "/testcontainers-host-sockets"
 )
 
+// hostGatewayExtraHost is the extra host mapping [HostInternal] to the host gateway of the daemon.
+const hostGatewayExtraHost = HostInternal + ":host-gateway"
+
 // sshPassword is a random password generated for the SSHD container.
 var sshPassword = uuid.NewString()
 
-// exposeHostPorts performs all the necessary steps to expose the host ports to the container, leveraging
+// HostAccessMode is how the containers reach the host ports exposed with
+// [WithHostPortAccess] or [ExposeHostPort].
+type HostAccessMode int
+
+const (
+	// HostAccessTunnel forwards the host ports over the SSH tunnel of an SSHD
+	// container. It's the default mode.
+	HostAccessTunnel HostAccessMode = iota
+
+	// HostAccessGateway maps [HostInternal] to the host gateway of the daemon,
+	// so that the containers reach the host directly, falling back to the SSH
+	// tunnel if the daemon doesn't support it, is remote or rootless, or if the
+	// tests run in a container.
+	//
+	// The host gateway isn't the loopback interface of the host, so the host
+	// servers must listen on an address reachable from the containers, e.g. on
+	// all the interfaces.
+	HostAccessGateway
+)
+
+// hostGatewaySupported returns why the host gateway can't be used to reach the
+// host from the containers, or nil if it can.
+func (p *DockerProvider) hostGatewaySupported(ctx context.Context) error {
+	info, err := p.client.Info(ctx, client.InfoOptions{})
+	if err != nil {
+		return fmt.Errorf("docker info: %w", err)
+	}
+
+	return checkHostGateway(p.client.DaemonHost(), p.client.ClientVersion(), info.Info, core.InAContainer())
+}
+
+// checkHostGateway returns why the host gateway of the daemon doesn't reach the
+// host running the tests, or nil if it does.
+func checkHostGateway(daemonHost string, apiVersion string, info system.Info, inContainer bool) error {
+	if err := checkLocalDaemon(daemonHost, inContainer); err != nil {
+		return err
+	}
+
+	// host-gateway was added in Docker 20.10.
+	if versions.LessThan(apiVersion, "1.41") {
+		return fmt.Errorf("API version %s older than 1.41", apiVersion)
+	}
+
+	for _, opt := range security.DecodeOptions(info.SecurityOptions) {
+		if opt.Name == "rootless" {
+			return errors.New("rootless docker daemon")
+		}
+	}
+
+	return nil
+}
+
+// checkLocalDaemon returns why the daemon doesn't share the network of the
+// host running the tests, or nil if it does.
+func checkLocalDaemon(daemonHost string, inContainer bool) error {
+	daemonURL, err := url.Parse(daemonHost)
+	if err != nil {
+		return fmt.Errorf("parse docker host: %w", err)
+	}
+
+	switch daemonURL.Scheme {
+	case "unix", "npipe":
+	case "http", "https", "tcp":
+		if ip := net.ParseIP(daemonURL.Hostname()); daemonURL.Hostname() != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return fmt.Errorf("remote docker host %s", daemonHost)
+		}
+	default:
+		return fmt.Errorf("unsupported docker host %s", daemonHost)
+	}
+
+	if inContainer {
+		return errors.New("running inside a container")
+	}
+
+	return nil
+}
+
+// withHostGateway maps [HostInternal] to the host gateway of the daemon in the request.
+func withHostGateway(req *ContainerRequest) {
+	// do not override the original HostConfigModifier
+	originalHCM := req.HostConfigModifier
+	req.HostConfigModifier = func(hostConfig *container.HostConfig) {
+		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, hostGatewayExtraHost)
+
+		if originalHCM != nil {
+			originalHCM(hostConfig)
+		}
+	}
+}
+
+// usesHostGateway returns whether the container reaches the host through the host gateway.
+func (c *DockerContainer) usesHostGateway(ctx context.Context) (bool, error) {
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return false, fmt.Errorf("inspect container: %w", err)
+	}
+
+	return inspect.HostConfig != nil && slices.Contains(inspect.HostConfig.ExtraHosts, hostGatewayExtraHost), nil
+}
+
+// exposeHostPorts performs all the necessary steps to expose the host ports and sockets to the container, leveraging
 // the SSHD container to create the tunnel, and the container lifecycle hooks to manage the tunnel lifecycle.
-// At least one port must be provided to expose.
+// At least one port or socket must be provided to expose. If hostGateway is true, the container reaches the
+// host ports through the host gateway, so the SSHD container isn't its [HostInternal] host.
 // The steps are:
 // 1. Create a new SSHD container.
-// 2. Expose the host ports to the container after the container is ready.
-// 3. Close the SSH sessions before killing the container.
-func exposeHostPorts(ctx context.Context, req *ContainerRequest, ports ...int) (sshdConnectHook ContainerLifecycleHooks, err error) {
-	if len(ports) == 0 {
-		return sshdConnectHook, errors.New("no ports to expose")
+// 2. Forward the host sockets to the volume shared with the container.
+// 3. Expose the host ports to the container after the container is ready.
+// 4. Close the SSH sessions before killing the container, and remove the volume once it's terminated.
+func exposeHostPorts(ctx context.Context, req *ContainerRequest, hostGateway bool, ports ...int) (sshdConnectHook ContainerLifecycleHooks, err error) {
+	if len(ports) == 0 && len(req.HostAccessSockets) == 0 {
+		return sshdConnectHook, errors.New("no ports or sockets to expose")
 	}
 
 	// Use the first network of the container to connect to the SSHD container.
@@ -63,30 +188,25 @@ func exposeHostPorts(ctx context.Context, req *ContainerRequest, ports ...int) (
 			return sshdConnectHook, fmt.Errorf("get network %q: %w", sshdFirstNetwork, err)
 		}
 
-		dockerNw := DockerNetwork{
-			ID:   nw.ID,
-			Name: nw.Name,
-		}
-
-		// WithNetwork reuses an already existing network, attaching the container to it.
-		// Finally it sets the network alias on that network to the given alias.
-		// TODO: Using an anonymous function to avoid cyclic dependencies with the network package.
-		withNetwork := func(aliases []string, nw *DockerNetwork) CustomizeRequestOption {
-			return func(req *GenericContainerRequest) error {
-				networkName := nw.Name
+		opts = append(opts, withSshdNetwork(nw.Name))
+	}
 
-				// attaching to the network because it was created with success or it already existed.
-				req.Networks = append(req.Networks, networkName)
+	// the host sockets are forwarded to a volume shared with the container.
+	var socketsVolume string
+	if len(req.HostAccessSockets) > 0 {
+		socketsVolume, err = createHostSocketsVolume(ctx)
+		if err != nil {
+			return sshdConnectHook, err
+		}
 
-				if req.NetworkAliases == nil {
-					req.NetworkAliases = make(map[string][]string)
-				}
-				req.NetworkAliases[networkName] = aliases
-				return nil
+		// Ensure the volume is removed in case of error, once the SSHD container is.
+		defer func() {
+			if err != nil {
+				err = errors.Join(err, removeHostSocketsVolume(socketsVolume))
 			}
-		}
+		}()
 
-		opts = append(opts, withNetwork([]string{HostInternal}, &dockerNw))
+		opts = append(opts, WithMounts(VolumeMount(socketsVolume, hostSocketsDir)))
 	}
 
 	// start the SSHD container with the provided options
@@ -102,24 +222,19 @@ func exposeHostPorts(ctx context.Context, req *ContainerRequest, ports ...int) (
 	}
 
 	// IP in the first network of the container.
-	inspect, err := sshdContainer.Inspect(ctx)
+	sshdIP, err := sshdContainer.networkIP(ctx, sshdFirstNetwork)
 	if err != nil {
-		return sshdConnectHook, fmt.Errorf("inspect sshd container: %w", err)
+		return sshdConnectHook, err
 	}
 
-	var sshdIP string
-	single := len(inspect.NetworkSettings.Networks) == 1
-	for name, nw := range inspect.NetworkSettings.Networks {
-		if name == sshdFirstNetwork || single {
-			if nw.IPAddress.IsValid() {
-				sshdIP = nw.IPAddress.String()
-				break
-			}
-		}
+	// The SSHD container is running, so forward the sockets before the container starts.
+	links, err := sshdContainer.exposeHostSockets(ctx, req.HostAccessSockets)
+	if err != nil {
+		return sshdConnectHook, err
 	}
 
-	if sshdIP == "" {
-		return sshdConnectHook, errors.New("sshd container IP not found")
+	if socketsVolume != "" {
+		req.Mounts = append(req.Mounts, VolumeMount(socketsVolume, hostSocketsDir))
 	}
 
 	if req.HostConfigModifier == nil {
@@ -131,7 +246,9 @@ func exposeHostPorts(ctx context.Context, req *ContainerRequest, ports ...int) (
 	req.HostConfigModifier = func(hostConfig *container.HostConfig) {
 		// adding the host internal alias to the container as an extra host
 		// to allow the container to reach the SSHD container.
-		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, fmt.Sprintf("%s:%s", HostInternal, sshdIP))
+		if !hostGateway {
+			hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, fmt.Sprintf("%s:%s", HostInternal, sshdIP))
+		}
 
 		modes := []container.NetworkMode{container.NetworkMode(sshdFirstNetwork), "none", "host"}
 		// if the container is not in one of the modes, attach it to the first network of the SSHD container
@@ -144,35 +261,532 @@ func exposeHostPorts(ctx context.Context, req *ContainerRequest, ports ...int) (
 		originalHCM(hostConfig)
 	}
 
-	stopHooks := []ContainerHook{
-		func(ctx context.Context, _ Container) error {
-			if ctx.Err() != nil {
-				// Context already canceled, need to create a new one to ensure
-				// the SSH session is closed.
-				var cancel context.CancelFunc
-				ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
-				defer cancel()
-			}
-
-			return TerminateContainer(sshdContainer, StopContext(ctx))
-		},
-	}
-
 	// after the container is ready, create the SSH tunnel
 	// for each exposed port from the host.
 	sshdConnectHook = ContainerLifecycleHooks{
 		PostReadies: []ContainerHook{
-			func(ctx context.Context, _ Container) error {
-				return sshdContainer.exposeHostPort(ctx, req.HostAccessPorts...)
+			func(ctx context.Context, c Container) error {
+				// Keep track of the tunnel, so that more ports can be exposed with ExposeHostPort,
+				// unless the container reaches the host ports through the host gateway.
+				if dockerContainer, ok := c.(*DockerContainer); ok && !hostGateway {
+					dockerContainer.hostAccessMtx.Lock()
+					dockerContainer.hostAccess = sshdContainer
+					dockerContainer.hostAccessMtx.Unlock()
+				}
+
+				return sshdContainer.exposeHostPort(ctx, ports...)
 			},
 		},
-		PostStops:     stopHooks,
-		PreTerminates: stopHooks,
+		// The tunnel is kept when the container is stopped, as its address
+		// in the extra hosts of the container can't be changed.
+		PreTerminates: []ContainerHook{sshdTerminateHook(sshdContainer)},
+	}
+
+	if len(links) > 0 {
+		sshdConnectHook.PostCreates = []ContainerHook{
+			func(ctx context.Context, c Container) error {
+				if err := copySymlinks(ctx, c, links); err != nil {
+					return err
+				}
+
+				return sshdContainer.chownHostSockets(ctx, c, slices.Collect(maps.Values(links)))
+			},
+		}
+		sshdConnectHook.PostTerminates = []ContainerHook{
+			func(ctx context.Context, c Container) error {
+				return removeVolume(ctx, c, socketsVolume)
+			},
+		}
 	}
 
 	return sshdConnectHook, nil
 }
 
+// exposeHostSockets forwards the host sockets, by container path, to sockets
+// in the shared volume of the SSHD container, returning the symbolic links from
+// the container paths to them.
+func (sshdC *sshdContainer) exposeHostSockets(ctx context.Context, sockets map[string]string) (map[string]string, error) {
+	containerPaths := slices.Sorted(maps.Keys(sockets))
+
+	links := make(map[string]string, len(sockets))
+	for i, containerPath := range containerPaths {
+		remotePath := fmt.Sprintf("%s/%d.sock", hostSocketsDir, i)
+		if err := sshdC.forwardSocket(ctx, sockets[containerPath], remotePath); err != nil {
+			return nil, errors.Join(fmt.Errorf("expose host socket %s: %w", sockets[containerPath], err), sshdC.closePorts())
+		}
+
+		links[containerPath] = remotePath
+	}
+
+	return links, nil
+}
+
+// forwardSocket forwards the host socket to the remote socket in the SSHD container.
+func (sshdC *sshdContainer) forwardSocket(ctx context.Context, hostPath string, remotePath string) (err error) {
+	sshdC.mtx.Lock()
+	defer sshdC.mtx.Unlock()
+
+	pf, err := newSocketForwarder(ctx, "localhost:"+sshdC.port, sshdC.sshConfig, remotePath, hostPath)
+	if err != nil {
+		return fmt.Errorf("new socket forwarder: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, pf.Close())
+		}
+	}()
+
+	sshdC.portForwarders = append(sshdC.portForwarders, pf)
+
+	return nil
+}
+
+// chownHostSockets changes the owner of the forwarded sockets to the user of the
+// container, as sshd creates them for its user only.
+func (sshdC *sshdContainer) chownHostSockets(ctx context.Context, c Container, sockets []string) error {
+	dockerContainer, ok := c.(*DockerContainer)
+	if !ok {
+		return fmt.Errorf("chown host sockets: unsupported container type %T", c)
+	}
+
+	inspect, err := dockerContainer.Inspect(ctx)
+	if err != nil {
+		return fmt.Errorf("inspect container: %w", err)
+	}
+
+	owner, err := containerOwner(ctx, dockerContainer, inspect.Config.User)
+	if err != nil {
+		return fmt.Errorf("container owner: %w", err)
+	}
+
+	slices.Sort(sockets)
+	code, reader, err := sshdC.Exec(ctx, append([]string{"chown", owner}, sockets...), tcexec.Multiplexed())
+	if err != nil {
+		return fmt.Errorf("chown host sockets: %w", err)
+	}
+
+	if code != 0 {
+		output, _ := io.ReadAll(reader)
+		return fmt.Errorf("chown host sockets: exit code %d: %s", code, output)
+	}
+
+	return nil
+}
+
+// containerOwner returns the numeric owner, uid or uid:gid, of the files of the
+// container running as user, in the "user[:group]" format of the container
+// configuration. The names are looked up in the /etc/passwd and /etc/group
+// files of the container, which doesn't need to be running.
+func containerOwner(ctx context.Context, c *DockerContainer, user string) (string, error) {
+	name, group, _ := strings.Cut(user, ":")
+	if name == "" {
+		name = "0"
+	}
+
+	uid, err := lookupContainerID(ctx, c, "/etc/passwd", name)
+	if err != nil {
+		return "", fmt.Errorf("user: %w", err)
+	}
+
+	if group == "" {
+		return uid, nil
+	}
+
+	gid, err := lookupContainerID(ctx, c, "/etc/group", group)
+	if err != nil {
+		return "", fmt.Errorf("group: %w", err)
+	}
+
+	return uid + ":" + gid, nil
+}
+
+// lookupContainerID returns the ID of name in the file of the container,
+// or name if it's already numeric.
+func lookupContainerID(ctx context.Context, c *DockerContainer, file string, name string) (string, error) {
+	if _, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return name, nil
+	}
+
+	r, err := c.CopyFileFromContainer(ctx, file)
+	if err != nil {
+		return "", fmt.Errorf("copy %s: %w", file, err)
+	}
+	defer r.Close()
+
+	return lookupID(r, name)
+}
+
+// lookupID returns the ID of name in r, in the format of /etc/passwd or /etc/group.
+func lookupID(r io.Reader, name string) (string, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) > 2 && fields[0] == name {
+			return fields[2], nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("read: %w", err)
+	}
+
+	return "", fmt.Errorf("%q not found", name)
+}
+
+// copySymlinks creates the symbolic links, by path, in the container,
+// creating their parent directories if needed.
+func copySymlinks(ctx context.Context, c Container, links map[string]string) error {
+	dockerContainer, ok := c.(*DockerContainer)
+	if !ok {
+		return fmt.Errorf("create symlinks: unsupported container type %T", c)
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, linkPath := range slices.Sorted(maps.Keys(links)) {
+		hdr := &tar.Header{
+			Typeflag: tar.TypeSymlink,
+			Name:     strings.TrimPrefix(linkPath, "/"),
+			Linkname: links[linkPath],
+			Mode:     0o777,
+			ModTime:  time.Now(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("write symlink header: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close tar writer: %w", err)
+	}
+
+	_, err := dockerContainer.provider.client.CopyToContainer(ctx, dockerContainer.ID, client.CopyToContainerOptions{
+		DestinationPath: "/",
+		Content:         &buf,
+	})
+	if err != nil {
+		return fmt.Errorf("copy symlinks to container: %w", err)
+	}
+
+	return nil
+}
+
+// createHostSocketsVolume creates the volume the host sockets are forwarded to,
+// labelled so that it's removed by the reaper if the container is not terminated.
+func createHostSocketsVolume(ctx context.Context) (string, error) {
+	cli, err := NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return "", fmt.Errorf("docker client: %w", err)
+	}
+	defer cli.Close()
+
+	name := "testcontainers-host-sockets-" + uuid.NewString()
+	if _, err = cli.VolumeCreate(ctx, client.VolumeCreateOptions{
+		Name:   name,
+		Labels: GenericLabels(),
+	}); err != nil {
+		return "", fmt.Errorf("volume create %q: %w", name, err)
+	}
+
+	return name, nil
+}
+
+// removeHostSocketsVolume removes the volume the host sockets are forwarded to.
+func removeHostSocketsVolume(volume string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cli, err := NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("docker client: %w", err)
+	}
+	defer cli.Close()
+
+	if _, err = cli.VolumeRemove(ctx, volume, client.VolumeRemoveOptions{Force: true}); !isCleanupSafe(err) {
+		return fmt.Errorf("volume remove %q: %w", volume, err)
+	}
+
+	return nil
+}
+
+// removeVolume removes the volume once the container using it is terminated.
+func removeVolume(ctx context.Context, c Container, volume string) error {
+	dockerContainer, ok := c.(*DockerContainer)
+	if !ok || dockerContainer.provider == nil {
+		return nil
+	}
+
+	_, err := dockerContainer.provider.client.VolumeRemove(ctx, volume, client.VolumeRemoveOptions{Force: true})
+	if !isCleanupSafe(err) {
+		return fmt.Errorf("volume remove %q: %w", volume, err)
+	}
+
+	return nil
+}
+
+// withSshdNetwork attaches the SSHD container to the existing network, with the
+// [HostInternal] alias, so that the containers of the network can reach it.
+// It doesn't use the network package to avoid cyclic dependencies.
+func withSshdNetwork(networkName string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.Networks = append(req.Networks, networkName)
+
+		if req.NetworkAliases == nil {
+			req.NetworkAliases = make(map[string][]string)
+		}
+		req.NetworkAliases[networkName] = []string{HostInternal}
+		return nil
+	}
+}
+
+// sshdTerminateHook returns the hook terminating the SSHD container when the
+// container is terminated, detaching the tunnel from the container.
+func sshdTerminateHook(sshd *sshdContainer) ContainerHook {
+	return func(ctx context.Context, c Container) error {
+		if dockerContainer, ok := c.(*DockerContainer); ok {
+			dockerContainer.hostAccessMtx.Lock()
+			if dockerContainer.hostAccess == sshd {
+				dockerContainer.hostAccess = nil
+			}
+			dockerContainer.hostAccessMtx.Unlock()
+		}
+
+		if ctx.Err() != nil {
+			// Context already canceled, need to create a new one to ensure
+			// the SSH session is closed.
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+		}
+
+		return TerminateContainer(sshd, StopContext(ctx))
+	}
+}
+
+// ExposeHostPort exposes the host port to the running container, which reaches it
+// at [HostInternal]:port, e.g. when the port of a server started by the test is
+// only known once the container is running. The port is a port number with an
+// optional protocol, e.g. "8080" or "53/udp", defaulting to tcp. Exposing a port
+// already exposed is a no-op, as well as exposing a port to a container reaching
+// the host through the host gateway, see [HostAccessGateway].
+//
+// The port is forwarded over the SSH tunnel of the container if it was created
+// with [WithHostPortAccess]. Otherwise the tunnel is started, attached to the
+// first network of the container, and [HostInternal] is added to the /etc/hosts
+// file of the container, which needs a shell running as root. The tunnel is kept
+// when the container is stopped, so that the ports are exposed again once it's
+// started, e.g. by [DockerContainer.Restart], and removed when it's terminated.
+//
+// SSH only forwards TCP, so UDP datagrams are relayed over a TCP connection per
+// peer, by a sidecar container sharing the network namespace of the tunnel,
+// framed to keep their boundaries.
+func ExposeHostPort(ctx context.Context, ctr Container, port string) error {
+	p, err := parseHostPort(port)
+	if err != nil {
+		return err
+	}
+
+	dockerContainer, ok := ctr.(*DockerContainer)
+	if !ok {
+		return fmt.Errorf("expose host port: unsupported container type %T", ctr)
+	}
+
+	gateway, err := dockerContainer.usesHostGateway(ctx)
+	if err != nil {
+		return fmt.Errorf("expose host port %s: %w", p, err)
+	}
+
+	if gateway {
+		// All the host ports are reachable.
+		return nil
+	}
+
+	sshd, err := dockerContainer.hostPortAccess(ctx)
+	if err != nil {
+		return fmt.Errorf("expose host port %s: %w", p, err)
+	}
+
+	if err := sshd.forward(ctx, p); err != nil {
+		return fmt.Errorf("expose host port %s: %w", p, err)
+	}
+
+	return nil
+}
+
+// UnexposeHostPort stops exposing the host port to the running container,
+// exposed by [ExposeHostPort] or [WithHostPortAccess]. The port is a port number
+// with an optional protocol, e.g. "8080" or "53/udp", defaulting to tcp.
+func UnexposeHostPort(ctx context.Context, ctr Container, port string) error {
+	p, err := parseHostPort(port)
+	if err != nil {
+		return err
+	}
+
+	dockerContainer, ok := ctr.(*DockerContainer)
+	if !ok {
+		return fmt.Errorf("unexpose host port: unsupported container type %T", ctr)
+	}
+
+	dockerContainer.hostAccessMtx.Lock()
+	sshd := dockerContainer.hostAccess
+	dockerContainer.hostAccessMtx.Unlock()
+
+	if sshd == nil {
+		if gateway, err := dockerContainer.usesHostGateway(ctx); err == nil && gateway {
+			return fmt.Errorf("unexpose host port %s: host ports are reachable through the host gateway", p)
+		}
+
+		return fmt.Errorf("unexpose host port %s: no host port exposed", p)
+	}
+
+	if err := sshd.unforward(ctx, p); err != nil {
+		return fmt.Errorf("unexpose host port %s: %w", p, err)
+	}
+
+	return nil
+}
+
+// parseHostPort parses the host port, which protocol defaults to tcp.
+func parseHostPort(port string) (dockernetwork.Port, error) {
+	p, err := dockernetwork.ParsePort(port)
+	if err != nil {
+		return dockernetwork.Port{}, fmt.Errorf("parse host port: %w", err)
+	}
+
+	if p.Num() == 0 {
+		return dockernetwork.Port{}, fmt.Errorf("invalid host port %q", port)
+	}
+
+	switch p.Proto() {
+	case dockernetwork.TCP, dockernetwork.UDP:
+		return p, nil
+	default:
+		return dockernetwork.Port{}, fmt.Errorf("host port %s: unsupported protocol %s", p.Port(), p.Proto())
+	}
+}
+
+// hostPortAccess returns the SSH tunnel of the running container, starting it if needed.
+func (c *DockerContainer) hostPortAccess(ctx context.Context) (sshd *sshdContainer, err error) {
+	c.hostAccessMtx.Lock()
+	defer c.hostAccessMtx.Unlock()
+
+	if c.hostAccess != nil {
+		return c.hostAccess, nil
+	}
+
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("inspect container: %w", err)
+	}
+
+	// Use the first network of the container, preferring a user-defined one,
+	// as the other containers of the network can resolve the alias too.
+	var networks []string
+	if inspect.NetworkSettings != nil {
+		for name := range inspect.NetworkSettings.Networks {
+			networks = append(networks, name)
+		}
+	}
+	sort.Strings(networks)
+
+	var networkName string
+	for _, name := range networks {
+		if networkName == "" || networkName == "bridge" {
+			networkName = name
+		}
+	}
+
+	switch networkName {
+	case "":
+		return nil, errors.New("container is not attached to any network")
+	case "host", "none":
+		return nil, fmt.Errorf("network mode %s not supported", networkName)
+	}
+
+	var opts []ContainerCustomizer
+	if networkName != "bridge" {
+		opts = append(opts, withSshdNetwork(networkName))
+	}
+
+	sshd, err = newSshdContainer(ctx, opts...)
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, TerminateContainer(sshd))
+		}
+	}()
+	if err != nil {
+		return nil, fmt.Errorf("new sshd container: %w", err)
+	}
+
+	sshdIP, err := sshd.networkIP(ctx, networkName)
+	if err != nil {
+		return nil, err
+	}
+
+	sshd.hostInternalIP = sshdIP
+	if err = c.addHostInternal(ctx, sshdIP); err != nil {
+		return nil, err
+	}
+
+	if !c.hostAccessHooked {
+		// The hooks manage the current tunnel of the container, so they're only added once.
+		c.addLifecycleHooks(ContainerLifecycleHooks{
+			PostStarts:    []ContainerHook{c.restoreHostInternalHook},
+			PreTerminates: []ContainerHook{c.terminateHostAccessHook},
+		})
+		c.hostAccessHooked = true
+	}
+	c.hostAccess = sshd
+
+	return sshd, nil
+}
+
+// addHostInternal adds [HostInternal] to the /etc/hosts file of the container,
+// as the extra hosts can't be changed once the container is created.
+func (c *DockerContainer) addHostInternal(ctx context.Context, ip string) error {
+	command := fmt.Sprintf("grep -q ' %[2]s$' /etc/hosts || echo '%[1]s %[2]s' >> /etc/hosts", ip, HostInternal)
+	code, reader, err := c.Exec(ctx, []string{"sh", "-c", command}, tcexec.Multiplexed())
+	if err != nil {
+		return fmt.Errorf("add %s to /etc/hosts: %w", HostInternal, err)
+	}
+
+	if code != 0 {
+		output, _ := io.ReadAll(reader)
+		return fmt.Errorf("add %s to /etc/hosts: exit code %d: %s", HostInternal, code, output)
+	}
+
+	return nil
+}
+
+// restoreHostInternalHook adds [HostInternal] back to the /etc/hosts file of the
+// container once started again, as Docker recreates the file, if the tunnel was
+// started by [ExposeHostPort].
+func (c *DockerContainer) restoreHostInternalHook(ctx context.Context, _ Container) error {
+	c.hostAccessMtx.Lock()
+	sshd := c.hostAccess
+	c.hostAccessMtx.Unlock()
+
+	if sshd == nil || sshd.hostInternalIP == "" {
+		return nil
+	}
+
+	return c.addHostInternal(ctx, sshd.hostInternalIP)
+}
+
+// terminateHostAccessHook terminates the tunnel started by [ExposeHostPort]
+// when the container is terminated.
+func (c *DockerContainer) terminateHostAccessHook(ctx context.Context, ctr Container) error {
+	c.hostAccessMtx.Lock()
+	sshd := c.hostAccess
+	c.hostAccessMtx.Unlock()
+
+	if sshd == nil {
+		return nil
+	}
+
+	return sshdTerminateHook(sshd)(ctx, ctr)
+}
+
 // newSshdContainer creates a new SSHD container with the provided options.
 func newSshdContainer(ctx context.Context, opts ...ContainerCustomizer) (*sshdContainer, error) {
 	moduleOpts := make([]ContainerCustomizer, 0, 3+len(opts))
@@ -205,15 +819,26 @@ func newSshdContainer(ctx context.Context, opts ...ContainerCustomizer) (*sshdCo
 // It's an internal type that extends the DockerContainer type, to add the SSH tunnelling capabilities.
 type sshdContainer struct {
 	Container
-	port           string
-	sshConfig      *ssh.ClientConfig
+	port      string
+	sshConfig *ssh.ClientConfig
+
+	// mtx protects the port forwarders and the UDP relay.
+	mtx            sync.Mutex
 	portForwarders []*portForwarder
+
+	// udpRelay is the sidecar relaying the UDP datagrams, started on the first UDP port.
+	udpRelay *networkSidecar
+
+	// hostInternalIP is the IP of [HostInternal] added to the /etc/hosts file of
+	// the container by [ExposeHostPort], empty if set in its extra hosts.
+	hostInternalIP string
 }
 
 // Terminate stops the container and closes the SSH session
 func (sshdC *sshdContainer) Terminate(ctx context.Context, opts ...TerminateOption) error {
 	return errors.Join(
 		sshdC.closePorts(),
+		sshdC.closeUDPRelay(),
 		sshdC.Container.Terminate(ctx, opts...),
 	)
 }
@@ -222,12 +847,16 @@ func (sshdC *sshdContainer) Terminate(ctx context.Context, opts ...TerminateOpti
 func (sshdC *sshdContainer) Stop(ctx context.Context, timeout *time.Duration) error {
 	return errors.Join(
 		sshdC.closePorts(),
+		sshdC.closeUDPRelay(),
 		sshdC.Container.Stop(ctx, timeout),
 	)
 }
 
 // closePorts closes all port forwarders.
 func (sshdC *sshdContainer) closePorts() error {
+	sshdC.mtx.Lock()
+	defer sshdC.mtx.Unlock()
+
 	var errs []error
 	for _, pfw := range sshdC.portForwarders {
 		if err := pfw.Close(); err != nil {
@@ -238,6 +867,47 @@ func (sshdC *sshdContainer) closePorts() error {
 	return errors.Join(errs...)
 }
 
+// closeUDPRelay terminates the UDP relay sidecar, if any.
+func (sshdC *sshdContainer) closeUDPRelay() error {
+	sshdC.mtx.Lock()
+	defer sshdC.mtx.Unlock()
+
+	if sshdC.udpRelay == nil {
+		return nil
+	}
+
+	err := TerminateContainer(sshdC.udpRelay.DockerContainer)
+	sshdC.udpRelay = nil
+	if err != nil {
+		return fmt.Errorf("terminate udp relay: %w", err)
+	}
+
+	return nil
+}
+
+// networkIP returns the IP of the SSHD container in the network, or in its only network.
+func (sshdC *sshdContainer) networkIP(ctx context.Context, networkName string) (string, error) {
+	inspect, err := sshdC.Inspect(ctx)
+	if err != nil {
+		return "", fmt.Errorf("inspect sshd container: %w", err)
+	}
+
+	single := len(inspect.NetworkSettings.Networks) == 1
+	for name, nw := range inspect.NetworkSettings.Networks {
+		if name == networkName || single {
+			switch {
+			case nw.IPAddress.IsValid():
+				return nw.IPAddress.String(), nil
+			case nw.GlobalIPv6Address.IsValid():
+				// IPv6-only network.
+				return nw.GlobalIPv6Address.String(), nil
+			}
+		}
+	}
+
+	return "", errors.New("sshd container IP not found")
+}
+
 // clientConfig sets up the SSHD client configuration.
 func (sshdC *sshdContainer) clientConfig(ctx context.Context) error {
 	mappedPort, err := sshdC.MappedPort(ctx, sshPort)
@@ -263,25 +933,207 @@ func (sshdC *sshdContainer) exposeHostPort(ctx context.Context, ports ...int) (e
 		}
 	}()
 	for _, port := range ports {
-		pf, err := newPortForwarder(ctx, "localhost:"+sshdC.port, sshdC.sshConfig, port)
+		p, err := parseHostPort(strconv.Itoa(port))
 		if err != nil {
-			return fmt.Errorf("new port forwarder: %w", err)
+			return err
+		}
+
+		if err := sshdC.forward(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// forward forwards the host port to the SSHD container, unless it's already forwarded.
+func (sshdC *sshdContainer) forward(ctx context.Context, port dockernetwork.Port) (err error) {
+	sshdC.mtx.Lock()
+	defer sshdC.mtx.Unlock()
+
+	for _, pf := range sshdC.portForwarders {
+		if pf.port == port {
+			return nil
 		}
+	}
+
+	pf, err := newPortForwarder(ctx, "localhost:"+sshdC.port, sshdC.sshConfig, port)
+	if err != nil {
+		return fmt.Errorf("new port forwarder: %w", err)
+	}
+
+	if port.Proto() == dockernetwork.UDP {
+		defer func() {
+			if err != nil {
+				err = errors.Join(err, pf.Close())
+			}
+		}()
 
-		sshdC.portForwarders = append(sshdC.portForwarders, pf)
+		if pf.relay, err = sshdC.relayUDP(ctx, port, pf.listener.Addr()); err != nil {
+			return err
+		}
 	}
 
+	sshdC.portForwarders = append(sshdC.portForwarders, pf)
+
 	return nil
 }
 
-// portForwarder forwards a port from the container to the host.
+// unforward closes the forwarder of the host port.
+func (sshdC *sshdContainer) unforward(_ context.Context, port dockernetwork.Port) error {
+	sshdC.mtx.Lock()
+	defer sshdC.mtx.Unlock()
+
+	for i, pf := range sshdC.portForwarders {
+		if pf.port == port {
+			sshdC.portForwarders = slices.Delete(sshdC.portForwarders, i, i+1)
+			return pf.Close()
+		}
+	}
+
+	return errors.New("port not exposed")
+}
+
+// relayUDP starts relaying the UDP datagrams received on the port of the SSHD
+// container to the remote TCP address, forwarded to the host. The caller must
+// hold the lock.
+func (sshdC *sshdContainer) relayUDP(ctx context.Context, port dockernetwork.Port, remote net.Addr) (*ExecProcess, error) {
+	tcpAddr, ok := remote.(*net.TCPAddr)
+	if !ok {
+		return nil, fmt.Errorf("unexpected remote address %s", remote)
+	}
+
+	if sshdC.udpRelay == nil {
+		relay, err := newNetworkSidecar(ctx, sshdC.GetContainerID())
+		if err == nil {
+			// The relay is run by the python3 interpreter of the network tools image.
+			if err = relay.run(ctx, "command -v python3 >/dev/null || { echo 'python3 not found in "+networkToolsImage+"' >&2; exit 1; }"); err != nil {
+				err = fmt.Errorf("check python3: %w", err)
+			}
+		}
+		if err != nil {
+			if relay != nil {
+				err = errors.Join(err, TerminateContainer(relay.DockerContainer))
+			}
+			return nil, fmt.Errorf("udp relay: %w", err)
+		}
+		sshdC.udpRelay = relay
+	}
+
+	process, err := sshdC.udpRelay.StartProcess(ctx, []string{
+		"python3", "-c", udpRelayScript, port.Port(), strconv.Itoa(tcpAddr.Port),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("start udp relay: %w", err)
+	}
+
+	// The process is started, but the socket could be bound later.
+	if err = sshdC.udpRelay.run(ctx, udpListeningCommand(port)); err != nil {
+		return nil, errors.Join(fmt.Errorf("udp relay not listening: %w", err), process.Stop(ctx))
+	}
+
+	return process, nil
+}
+
+// udpRelayScript relays the UDP datagrams received on the port given as first
+// argument over a TCP connection per peer to the port given as second argument,
+// closed once idle for a minute. The datagrams are framed on the TCP connection,
+// prefixed by their length as a big-endian uint16, see [writeDatagramFrames].
+// The UDP socket is dual-stack, receiving both the IPv4 and IPv6 datagrams,
+// unless IPv6 is not available.
+const udpRelayScript = `import selectors, socket, struct, sys, time
+
+port, remote = int(sys.argv[1]), int(sys.argv[2])
+
+def bind(family, host):
+    s = socket.socket(family, socket.SOCK_DGRAM)
+    s.setsockopt(socket.SOL_SOCKET, socket.SO_REUSEADDR, 1)
+    if family == socket.AF_INET6:
+        s.setsockopt(socket.IPPROTO_IPV6, socket.IPV6_V6ONLY, 0)
+    s.bind((host, port))
+    return s
+
+try:
+    udp = bind(socket.AF_INET6, "::")
+except OSError:
+    udp = bind(socket.AF_INET, "0.0.0.0")
+sel = selectors.DefaultSelector()
+sel.register(udp, selectors.EVENT_READ)
+peers = {}
+
+def close(addr):
+    conn = peers.pop(addr)[0]
+    sel.unregister(conn)
+    conn.close()
+
+while True:
+    for key, _ in sel.select(10):
+        if key.fileobj is udp:
+            data, addr = udp.recvfrom(65535)
+            if addr not in peers:
+                try:
+                    conn = socket.create_connection(("127.0.0.1", remote))
+                except OSError:
+                    continue
+                peers[addr] = [conn, b"", 0]
+                sel.register(conn, selectors.EVENT_READ, addr)
+            peer = peers[addr]
+            peer[2] = time.monotonic()
+            try:
+                peer[0].sendall(struct.pack(">H", len(data)) + data)
+            except OSError:
+                close(addr)
+            continue
+
+        addr = key.data
+        peer = peers.get(addr)
+        if peer is None:
+            continue
+        try:
+            data = peer[0].recv(65537)
+        except OSError:
+            data = b""
+        if not data:
+            close(addr)
+            continue
+        peer[1] += data
+        peer[2] = time.monotonic()
+        while len(peer[1]) >= 2:
+            size = struct.unpack(">H", peer[1][:2])[0]
+            if len(peer[1]) < 2 + size:
+                break
+            udp.sendto(peer[1][2:2 + size], addr)
+            peer[1] = peer[1][2 + size:]
+
+    now = time.monotonic()
+    for addr in [addr for addr, peer in peers.items() if now - peer[2] > 60]:
+        close(addr)
+`
+
+// udpListeningCommand returns the shell command which waits up to five seconds
+// for a UDP socket to be bound to the port.
+func udpListeningCommand(port dockernetwork.Port) string {
+	return fmt.Sprintf(`for i in $(seq 50); do
+  [ -n "$(ss -Hlun 'sport = :%s')" ] && exit 0
+  sleep 0.1
+done
+echo "port %s not bound" >&2
+exit 1`, port.Port(), port)
+}
+
+// portForwarder forwards a port or a socket from the container to the host.
 type portForwarder struct {
-	client      *ssh.Client
-	listener    net.Listener
-	dialTimeout time.Duration
-	localAddr   string
-	ctx         context.Context
-	cancel      context.CancelFunc
+	client       *ssh.Client
+	listener     net.Listener
+	dialTimeout  time.Duration
+	port         dockernetwork.Port // zero for sockets
+	localNetwork string
+	localAddr    string
+	ctx          context.Context
+	cancel       context.CancelFunc
+
+	// relay is the process relaying the UDP datagrams to the listener, for UDP ports.
+	relay *ExecProcess
 
 	// closeMtx protects the close operation
 	closeMtx sync.Mutex
@@ -290,7 +1142,52 @@ type portForwarder struct {
 
 // newPortForwarder creates a new running portForwarder for the given port.
 // The context is only used for the initial SSH connection.
-func newPortForwarder(ctx context.Context, sshDAddr string, sshConfig *ssh.ClientConfig, port int) (pf *portForwarder, err error) {
+//
+// UDP ports are forwarded from a remote TCP port allocated by the SSHD server,
+// on which the datagrams must be relayed.
+func newPortForwarder(ctx context.Context, sshDAddr string, sshConfig *ssh.ClientConfig, port dockernetwork.Port) (*portForwarder, error) {
+	remotePort := port.Port()
+	localAddr := "localhost:" + port.Port()
+	if port.Proto() == dockernetwork.UDP {
+		remotePort = "0"
+		// Dialing UDP doesn't connect, so it can't fall back to another address.
+		localAddr = "127.0.0.1:" + port.Port()
+	}
+
+	pf, err := newForwarder(ctx, sshDAddr, sshConfig, func(client *ssh.Client) (net.Listener, error) {
+		listener, err := client.Listen("tcp", "localhost:"+remotePort)
+		if err != nil {
+			return nil, fmt.Errorf("listening on remote port %s: %w", remotePort, err)
+		}
+
+		return listener, nil
+	}, string(port.Proto()), localAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	pf.port = port
+
+	return pf, nil
+}
+
+// newSocketForwarder creates a new running portForwarder for the given host
+// socket, from the remote socket path.
+// The context is only used for the initial SSH connection.
+func newSocketForwarder(ctx context.Context, sshDAddr string, sshConfig *ssh.ClientConfig, remotePath string, hostPath string) (*portForwarder, error) {
+	return newForwarder(ctx, sshDAddr, sshConfig, func(client *ssh.Client) (net.Listener, error) {
+		listener, err := client.ListenUnix(remotePath)
+		if err != nil {
+			return nil, fmt.Errorf("listening on remote socket %s: %w", remotePath, err)
+		}
+
+		return listener, nil
+	}, "unix", hostPath)
+}
+
+// newForwarder creates a new running portForwarder, forwarding the connections
+// accepted by the remote listener to the local address.
+func newForwarder(ctx context.Context, sshDAddr string, sshConfig *ssh.ClientConfig, listen func(*ssh.Client) (net.Listener, error), localNetwork string, localAddr string) (pf *portForwarder, err error) {
 	var d net.Dialer
 	conn, err := d.DialContext(ctx, "tcp", sshDAddr)
 	if err != nil {
@@ -311,20 +1208,21 @@ func newPortForwarder(ctx context.Context, sshDAddr string, sshConfig *ssh.Clien
 
 	client := ssh.NewClient(c, chans, reqs)
 
-	listener, err := client.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	listener, err := listen(client)
 	if err != nil {
-		return nil, fmt.Errorf("listening on remote port %d: %w", port, err)
+		return nil, errors.Join(err, client.Close())
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	pf = &portForwarder{
-		client:      client,
-		listener:    listener,
-		localAddr:   fmt.Sprintf("localhost:%d", port),
-		ctx:         ctx,
-		cancel:      cancel,
-		dialTimeout: time.Second * 2,
+		client:       client,
+		listener:     listener,
+		localNetwork: localNetwork,
+		localAddr:    localAddr,
+		ctx:          ctx,
+		cancel:       cancel,
+		dialTimeout:  time.Second * 2,
 	}
 
 	go pf.run()
@@ -345,6 +1243,14 @@ func (pf *portForwarder) Close() error {
 	}
 
 	var errs []error
+	if pf.relay != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := pf.relay.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop udp relay: %w", err))
+		}
+	}
 	if err := pf.listener.Close(); err != nil {
 		errs = append(errs, fmt.Errorf("close listener: %w", err))
 	}
@@ -387,7 +1293,7 @@ func (pf *portForwarder) tunnel(remote net.Conn) {
 	defer cancel()
 
 	var dialer net.Dialer
-	local, err := dialer.DialContext(ctx, "tcp", pf.localAddr)
+	local, err := dialer.DialContext(ctx, pf.localNetwork, pf.localAddr)
 	if err != nil {
 		// Nothing we can do with the error.
 		return
@@ -396,14 +1302,20 @@ func (pf *portForwarder) tunnel(remote net.Conn) {
 
 	ctx, cancel = context.WithCancel(pf.ctx)
 
+	toLocal, toRemote := io.Copy, io.Copy
+	if pf.localNetwork == "udp" {
+		// The datagrams relayed over the tunnel are framed to keep their boundaries.
+		toLocal, toRemote = readDatagramFrames, writeDatagramFrames
+	}
+
 	go func() {
 		defer cancel()
-		io.Copy(local, remote) //nolint:errcheck // Nothing useful we can do with the error.
+		toLocal(local, remote) //nolint:errcheck // Nothing useful we can do with the error.
 	}()
 
 	go func() {
 		defer cancel()
-		io.Copy(remote, local) //nolint:errcheck // Nothing useful we can do with the error.
+		toRemote(remote, local) //nolint:errcheck // Nothing useful we can do with the error.
 	}()
 
 	// Wait for the context to be done before returning which triggers
@@ -411,3 +1323,51 @@ func (pf *portForwarder) tunnel(remote net.Conn) {
 	// blocking forever on unused connections.
 	<-ctx.Done()
 }
+
+// maxDatagramSize is the maximum size of a UDP datagram payload, which fits
+// the uint16 length prefix of its frame.
+const maxDatagramSize = 1<<16 - 1
+
+// readDatagramFrames writes each frame read from src, prefixed by its length as
+// a big-endian uint16, as a single datagram to dst, until an error occurs.
+func readDatagramFrames(dst io.Writer, src io.Reader) (int64, error) {
+	r := bufio.NewReader(src)
+	buf := make([]byte, maxDatagramSize)
+	var size [2]byte
+	var written int64
+	for {
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return written, err
+		}
+
+		n := binary.BigEndian.Uint16(size[:])
+		if _, err := io.ReadFull(r, buf[:n]); err != nil {
+			return written, err
+		}
+
+		if _, err := dst.Write(buf[:n]); err != nil {
+			return written, err
+		}
+		written += int64(n)
+	}
+}
+
+// writeDatagramFrames writes each datagram read from src to dst as a frame,
+// prefixed by its length as a big-endian uint16, until an error occurs.
+// Each read of src must return a single datagram, as for a UDP connection.
+func writeDatagramFrames(dst io.Writer, src io.Reader) (int64, error) {
+	buf := make([]byte, 2+maxDatagramSize)
+	var written int64
+	for {
+		n, err := src.Read(buf[2:])
+		if err != nil {
+			return written, err
+		}
+
+		binary.BigEndian.PutUint16(buf, uint16(n)) //nolint:gosec // n is at most maxDatagramSize.
+		if _, err := dst.Write(buf[:2+n]); err != nil {
+			return written, err
+		}
+		written += int64(n)
+	}
+}
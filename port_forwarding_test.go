@@ -7,9 +7,15 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
 	"github.com/stretchr/testify/require"
 
 	"github.com/testcontainers/testcontainers-go"
@@ -151,3 +157,372 @@ func containerHasNoHostAccess(t *testing.T, c testcontainers.Container, ports ..
 		require.Contains(t, response, "bad address")
 	}
 }
+
+func TestExposeHostPort(t *testing.T) {
+	newServer := func(t *testing.T) int {
+		t.Helper()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, expectedResponse)
+		}))
+		t.Cleanup(server.Close)
+
+		return server.Listener.Addr().(*net.TCPAddr).Port
+	}
+
+	t.Run("running-container", func(t *testing.T) {
+		c, err := testcontainers.Run(context.Background(), "alpine", testcontainers.WithCmd("top"))
+		testcontainers.CleanupContainer(t, c)
+		require.NoError(t, err)
+
+		port := newServer(t)
+		containerHasNoHostAccess(t, c, port)
+
+		require.NoError(t, testcontainers.ExposeHostPort(context.Background(), c, strconv.Itoa(port)))
+		containerHasHostAccess(t, c, port)
+
+		// Exposing it again is a no-op.
+		require.NoError(t, testcontainers.ExposeHostPort(context.Background(), c, strconv.Itoa(port)))
+		containerHasHostAccess(t, c, port)
+
+		require.NoError(t, testcontainers.UnexposeHostPort(context.Background(), c, strconv.Itoa(port)))
+		code, _ := httpRequest(t, c, port)
+		require.NotZero(t, code)
+
+		err = testcontainers.UnexposeHostPort(context.Background(), c, strconv.Itoa(port))
+		require.ErrorContains(t, err, "port not exposed")
+	})
+
+	t.Run("running-container-network", func(t *testing.T) {
+		nw, err := network.New(context.Background())
+		require.NoError(t, err)
+		testcontainers.CleanupNetwork(t, nw)
+
+		c, err := testcontainers.Run(context.Background(), "alpine",
+			testcontainers.WithCmd("top"),
+			network.WithNetwork([]string{"myalpine"}, nw),
+		)
+		testcontainers.CleanupContainer(t, c)
+		require.NoError(t, err)
+
+		port := newServer(t)
+		require.NoError(t, testcontainers.ExposeHostPort(context.Background(), c, strconv.Itoa(port)))
+		containerHasHostAccess(t, c, port)
+	})
+
+	t.Run("host-port-access", func(t *testing.T) {
+		first := newServer(t)
+
+		c, err := testcontainers.Run(context.Background(), "alpine",
+			testcontainers.WithHostPortAccess(first),
+			testcontainers.WithCmd("top"),
+		)
+		testcontainers.CleanupContainer(t, c)
+		require.NoError(t, err)
+
+		second := newServer(t)
+		require.NoError(t, testcontainers.ExposeHostPort(context.Background(), c, strconv.Itoa(second)))
+		containerHasHostAccess(t, c, first, second)
+
+		require.NoError(t, testcontainers.UnexposeHostPort(context.Background(), c, strconv.Itoa(first)))
+		code, _ := httpRequest(t, c, first)
+		require.NotZero(t, code)
+		containerHasHostAccess(t, c, second)
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		port := newServer(t)
+
+		c, err := testcontainers.Run(context.Background(), "alpine", testcontainers.WithCmd("top"))
+		testcontainers.CleanupContainer(t, c)
+		require.NoError(t, err)
+
+		require.NoError(t, testcontainers.ExposeHostPort(context.Background(), c, strconv.Itoa(port)))
+		containerHasHostAccess(t, c, port)
+
+		// The tunnel is kept, and HostInternal is restored in /etc/hosts.
+		require.NoError(t, c.Restart(context.Background()))
+		containerHasHostAccess(t, c, port)
+
+		second := newServer(t)
+		require.NoError(t, testcontainers.ExposeHostPort(context.Background(), c, strconv.Itoa(second)))
+		containerHasHostAccess(t, c, port, second)
+	})
+
+	t.Run("restart-host-port-access", func(t *testing.T) {
+		first := newServer(t)
+
+		c, err := testcontainers.Run(context.Background(), "alpine",
+			testcontainers.WithHostPortAccess(first),
+			testcontainers.WithCmd("top"),
+		)
+		testcontainers.CleanupContainer(t, c)
+		require.NoError(t, err)
+
+		require.NoError(t, c.Restart(context.Background()))
+		containerHasHostAccess(t, c, first)
+
+		second := newServer(t)
+		require.NoError(t, testcontainers.ExposeHostPort(context.Background(), c, strconv.Itoa(second)))
+		containerHasHostAccess(t, c, first, second)
+	})
+
+	t.Run("udp", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+
+		// Echo the datagrams back.
+		go func() {
+			buf := make([]byte, 1024)
+			for {
+				n, addr, err := conn.ReadFrom(buf)
+				if err != nil {
+					return
+				}
+				conn.WriteTo(buf[:n], addr) //nolint:errcheck // Best effort echo.
+			}
+		}()
+
+		port := conn.LocalAddr().(*net.UDPAddr).Port
+
+		c, err := testcontainers.Run(context.Background(), "alpine", testcontainers.WithCmd("top"))
+		testcontainers.CleanupContainer(t, c)
+		require.NoError(t, err)
+
+		require.NoError(t, testcontainers.ExposeHostPort(context.Background(), c, fmt.Sprintf("%d/udp", port)))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		code, reader, err := c.Exec(ctx,
+			[]string{"sh", "-c", fmt.Sprintf("echo ping | nc -u -w 2 %s %d", testcontainers.HostInternal, port)},
+			tcexec.Multiplexed(),
+		)
+		require.NoError(t, err)
+
+		bs, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Zero(t, code, string(bs))
+		require.Equal(t, "ping\n", string(bs))
+	})
+
+	t.Run("udp-back-to-back", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+
+		// Record and echo the datagrams back.
+		received := make(chan string, 10)
+		go func() {
+			buf := make([]byte, 1024)
+			for {
+				n, addr, err := conn.ReadFrom(buf)
+				if err != nil {
+					return
+				}
+				received <- string(buf[:n])
+				conn.WriteTo(buf[:n], addr) //nolint:errcheck // Best effort echo.
+			}
+		}()
+
+		port := conn.LocalAddr().(*net.UDPAddr).Port
+
+		c, err := testcontainers.Run(context.Background(), "nicolaka/netshoot:v0.13", testcontainers.WithCmd("sleep", "infinity"))
+		testcontainers.CleanupContainer(t, c)
+		require.NoError(t, err)
+
+		require.NoError(t, testcontainers.ExposeHostPort(context.Background(), c, fmt.Sprintf("%d/udp", port)))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// Send the datagrams from the same socket, without waiting for the replies.
+		script := fmt.Sprintf(`import socket
+s = socket.socket(socket.AF_INET, socket.SOCK_DGRAM)
+s.settimeout(5)
+for i in range(10):
+    s.sendto(b"ping%%d" %% i, (%q, %d))
+print(" ".join(s.recv(1024).decode() for _ in range(10)))`, testcontainers.HostInternal, port)
+
+		code, reader, err := c.Exec(ctx, []string{"python3", "-c", script}, tcexec.Multiplexed())
+		require.NoError(t, err)
+
+		bs, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Zero(t, code, string(bs))
+		require.Equal(t, "ping0 ping1 ping2 ping3 ping4 ping5 ping6 ping7 ping8 ping9\n", string(bs))
+
+		for i := range 10 {
+			require.Equal(t, "ping"+strconv.Itoa(i), <-received)
+		}
+	})
+
+	t.Run("udp-ipv6", func(t *testing.T) {
+		ctx := context.Background()
+
+		nw, err := network.New(ctx, network.WithEnableIPv6(), network.WithDisableIPv4())
+		testcontainers.CleanupNetwork(t, nw)
+		if err != nil {
+			t.Skipf("IPv6-only networks not supported by the Docker daemon: %s", err)
+		}
+
+		conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+
+		// Echo the datagrams back.
+		go func() {
+			buf := make([]byte, 1024)
+			for {
+				n, addr, err := conn.ReadFrom(buf)
+				if err != nil {
+					return
+				}
+				conn.WriteTo(buf[:n], addr) //nolint:errcheck // Best effort echo.
+			}
+		}()
+
+		port := conn.LocalAddr().(*net.UDPAddr).Port
+
+		c, err := testcontainers.Run(ctx, "nicolaka/netshoot:v0.13",
+			testcontainers.WithCmd("sleep", "infinity"),
+			network.WithNetwork(nil, nw),
+		)
+		testcontainers.CleanupContainer(t, c)
+		require.NoError(t, err)
+
+		require.NoError(t, testcontainers.ExposeHostPort(ctx, c, fmt.Sprintf("%d/udp", port)))
+
+		execCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		// The host is only reachable through the IPv6 address of the SSHD server container.
+		script := fmt.Sprintf(`import socket
+addr = socket.getaddrinfo(%q, %d, socket.AF_INET6, socket.SOCK_DGRAM)[0][4]
+s = socket.socket(socket.AF_INET6, socket.SOCK_DGRAM)
+s.settimeout(5)
+s.sendto(b"ping", addr)
+print(s.recv(1024).decode())`, testcontainers.HostInternal, port)
+
+		code, reader, err := c.Exec(execCtx, []string{"python3", "-c", script}, tcexec.Multiplexed())
+		require.NoError(t, err)
+
+		bs, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Zero(t, code, string(bs))
+		require.Equal(t, "ping\n", string(bs))
+	})
+
+	t.Run("invalid-port", func(t *testing.T) {
+		err := testcontainers.ExposeHostPort(context.Background(), &testcontainers.DockerContainer{}, "9000/sctp")
+		require.EqualError(t, err, "host port 9000: unsupported protocol sctp")
+	})
+}
+
+func TestExposeHostPorts_hostGateway(t *testing.T) {
+	// The host gateway isn't the loopback interface, so listen on all the interfaces.
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, expectedResponse)
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	// hostGatewayAccess {
+	c, err := testcontainers.Run(context.Background(), "alpine",
+		testcontainers.WithHostPortAccess(port),
+		testcontainers.WithHostAccessMode(testcontainers.HostAccessGateway),
+		testcontainers.WithCmd("top"),
+	)
+	// }
+	testcontainers.CleanupContainer(t, c)
+	require.NoError(t, err)
+
+	// Either through the host gateway or the SSH tunnel, depending on the daemon.
+	containerHasHostAccess(t, c, port)
+
+	// Exposing a port at runtime works in both cases.
+	require.NoError(t, testcontainers.ExposeHostPort(context.Background(), c, strconv.Itoa(port)))
+	containerHasHostAccess(t, c, port)
+}
+
+func TestExposeHostSockets(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, expectedResponse)
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	// hostSocketAccess {
+	c, err := testcontainers.Run(context.Background(), "nicolaka/netshoot:v0.13",
+		testcontainers.WithHostSocketAccess(socketPath, "/run/agent/agent.sock"),
+		testcontainers.WithCmd("sleep", "infinity"),
+		// The socket is owned by the user of the container.
+		testcontainers.WithConfigModifier(func(config *container.Config) {
+			config.User = "nobody"
+		}),
+	)
+	// }
+	testcontainers.CleanupContainer(t, c)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	code, reader, err := c.Exec(ctx,
+		[]string{"curl", "-sS", "--unix-socket", "/run/agent/agent.sock", "http://localhost/"},
+		tcexec.Multiplexed(),
+	)
+	require.NoError(t, err)
+
+	bs, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Zero(t, code, string(bs))
+	require.Equal(t, expectedResponse, string(bs))
+
+	code, reader, err = c.Exec(ctx, []string{"stat", "-L", "-c", "%U %a", "/run/agent/agent.sock"}, tcexec.Multiplexed())
+	require.NoError(t, err)
+
+	bs, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Zero(t, code, string(bs))
+	require.Equal(t, "nobody", strings.Fields(string(bs))[0])
+	require.NotEqual(t, "666", strings.Fields(string(bs))[1])
+
+	// The sockets volume is labelled for the reaper, and removed with the container.
+	inspect, err := c.Inspect(ctx)
+	require.NoError(t, err)
+
+	var volume string
+	for _, m := range inspect.Mounts {
+		if m.Destination == "/testcontainers-host-sockets" {
+			volume = m.Name
+		}
+	}
+	require.NotEmpty(t, volume)
+
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	require.NoError(t, err)
+	defer cli.Close()
+
+	vol, err := cli.VolumeInspect(ctx, volume, client.VolumeInspectOptions{})
+	require.NoError(t, err)
+	for k, v := range testcontainers.GenericLabels() {
+		require.Equal(t, v, vol.Volume.Labels[k], k)
+	}
+
+	require.NoError(t, testcontainers.TerminateContainer(c))
+	_, err = cli.VolumeInspect(ctx, volume, client.VolumeInspectOptions{})
+	require.True(t, errdefs.IsNotFound(err), err)
+}
@@ -0,0 +1,170 @@
+package testcontainers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/system"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHostPort(t *testing.T) {
+	t.Run("default-tcp", func(t *testing.T) {
+		p, err := parseHostPort("8080")
+		require.NoError(t, err)
+		require.Equal(t, network.MustParsePort("8080/tcp"), p)
+	})
+
+	t.Run("udp", func(t *testing.T) {
+		p, err := parseHostPort("53/udp")
+		require.NoError(t, err)
+		require.Equal(t, network.MustParsePort("53/udp"), p)
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		_, err := parseHostPort("0")
+		require.EqualError(t, err, `invalid host port "0"`)
+	})
+
+	t.Run("out-of-range", func(t *testing.T) {
+		_, err := parseHostPort("65536")
+		require.ErrorContains(t, err, "parse host port")
+	})
+
+	t.Run("unsupported-protocol", func(t *testing.T) {
+		_, err := parseHostPort("9000/sctp")
+		require.EqualError(t, err, "host port 9000: unsupported protocol sctp")
+	})
+}
+
+func TestCheckHostGateway(t *testing.T) {
+	rootless := system.Info{SecurityOptions: []string{"name=seccomp,profile=builtin", "name=rootless"}}
+
+	tests := []struct {
+		name        string
+		daemonHost  string
+		apiVersion  string
+		info        system.Info
+		inContainer bool
+		expectedErr string
+	}{
+		{name: "unix-socket", daemonHost: "unix:///var/run/docker.sock", apiVersion: "1.47"},
+		{name: "npipe", daemonHost: "npipe:////./pipe/docker_engine", apiVersion: "1.47"},
+		{name: "tcp-localhost", daemonHost: "tcp://localhost:2375", apiVersion: "1.47"},
+		{name: "tcp-loopback", daemonHost: "tcp://127.0.0.1:2375", apiVersion: "1.47"},
+		{name: "tcp-remote", daemonHost: "tcp://10.0.0.5:2376", apiVersion: "1.47", expectedErr: "remote docker host tcp://10.0.0.5:2376"},
+		{name: "ssh", daemonHost: "ssh://user@remote", apiVersion: "1.47", expectedErr: "unsupported docker host ssh://user@remote"},
+		{name: "in-container", daemonHost: "unix:///var/run/docker.sock", apiVersion: "1.47", inContainer: true, expectedErr: "running inside a container"},
+		{name: "old-api", daemonHost: "unix:///var/run/docker.sock", apiVersion: "1.40", expectedErr: "API version 1.40 older than 1.41"},
+		{name: "rootless", daemonHost: "unix:///run/user/1000/docker.sock", apiVersion: "1.47", info: rootless, expectedErr: "rootless docker daemon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkHostGateway(tt.daemonHost, tt.apiVersion, tt.info, tt.inContainer)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestWithHostGateway(t *testing.T) {
+	req := &ContainerRequest{
+		HostConfigModifier: func(hostConfig *container.HostConfig) {
+			hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, "db.internal:10.0.0.2")
+		},
+	}
+
+	withHostGateway(req)
+
+	hostConfig := &container.HostConfig{}
+	req.HostConfigModifier(hostConfig)
+	require.Equal(t, []string{"host.testcontainers.internal:host-gateway", "db.internal:10.0.0.2"}, hostConfig.ExtraHosts)
+}
+
+func TestDatagramFrames(t *testing.T) {
+	// Two connected UDP sockets, sending datagrams to each other.
+	sender, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer sender.Close()
+
+	receiver, err := net.DialUDP("udp4", nil, sender.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+	defer receiver.Close()
+
+	datagrams := [][]byte{[]byte("one"), []byte("two"), {}, bytes.Repeat([]byte("x"), 8192)}
+
+	// Send the datagrams back to back, before they're read.
+	for _, datagram := range datagrams {
+		_, err = sender.WriteTo(datagram, receiver.LocalAddr())
+		require.NoError(t, err)
+	}
+
+	// Frame them on a stream, as on the TCP connection of the tunnel.
+	var stream bytes.Buffer
+	require.NoError(t, receiver.SetReadDeadline(time.Now().Add(time.Second)))
+	_, err = writeDatagramFrames(&stream, receiver)
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	var frames []byte
+	for _, datagram := range datagrams {
+		frames = binary.BigEndian.AppendUint16(frames, uint16(len(datagram)))
+		frames = append(frames, datagram...)
+	}
+	require.Equal(t, frames, stream.Bytes())
+
+	// Each frame is written as a single datagram.
+	var written datagramRecorder
+	n, err := readDatagramFrames(&written, &stream)
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, int64(3+3+8192), n)
+	require.Equal(t, datagrams, written.datagrams)
+
+	_, err = readDatagramFrames(&written, bytes.NewReader([]byte{0, 4, 'p', 'i'}))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+// datagramRecorder records each write as a datagram.
+type datagramRecorder struct {
+	datagrams [][]byte
+}
+
+func (r *datagramRecorder) Write(p []byte) (int, error) {
+	r.datagrams = append(r.datagrams, bytes.Clone(p))
+	return len(p), nil
+}
+
+func TestLookupID(t *testing.T) {
+	const passwd = `root:x:0:0:root:/root:/bin/sh
+nobody:x:65534:65534:nobody:/nonexistent:/usr/sbin/nologin
+app:x:1000:1000::/home/app:/bin/sh
+`
+
+	t.Run("found", func(t *testing.T) {
+		id, err := lookupID(strings.NewReader(passwd), "app")
+		require.NoError(t, err)
+		require.Equal(t, "1000", id)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		_, err := lookupID(strings.NewReader(passwd), "postgres")
+		require.EqualError(t, err, `"postgres" not found`)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		_, err := lookupID(strings.NewReader(passwd), "ap")
+		require.EqualError(t, err, `"ap" not found`)
+	})
+}
@@ -100,10 +100,18 @@ func (lc *StdoutLogConsumer) Accept(l Log) {
 // container is stopped when the function ends.
 //
 // before any error check. If container is nil, it's a no-op.
+//
+// If the test failed and an artifacts directory is configured, with
+// [WithFailureArtifacts] or the TESTCONTAINERS_ARTIFACTS_DIR environment
+// variable, the artifacts of the container are collected before terminating it.
 func CleanupContainer(tb testing.TB, ctr Container, options ...TerminateOption) {
 	tb.Helper()
 
 	tb.Cleanup(func() {
+		if dockerContainer, ok := ctr.(*DockerContainer); ok && dockerContainer != nil && tb.Failed() {
+			dockerContainer.collectFailureArtifacts(tb)
+		}
+
 		noErrorOrIgnored(tb, TerminateContainer(ctr, options...))
 	})
 }
@@ -122,6 +130,19 @@ func CleanupNetwork(tb testing.TB, network Network) {
 	})
 }
 
+// CleanupPartition is a helper function that schedules the network partition
+// to be healed when the test ends, restoring the connectivity of the containers.
+// If partition is nil, it's a no-op.
+func CleanupPartition(tb testing.TB, partition *NetworkPartition) {
+	tb.Helper()
+
+	tb.Cleanup(func() {
+		if partition != nil {
+			noErrorOrIgnored(tb, partition.Heal(context.Background()))
+		}
+	})
+}
+
 // noErrorOrIgnored is a helper function that checks if the error is nil or an error
 // we can ignore.
 func noErrorOrIgnored(tb testing.TB, err error) {
@@ -202,3 +223,131 @@ func RequireContainerExec(ctx context.Context, t *testing.T, container Container
 	require.NoError(t, err)
 	return string(checkBytes)
 }
+
+// RequireNoUnexpectedWrites is a helper function that fails the test if the
+// filesystem of the container has been changed outside the allowed paths,
+// or any of their descendants, listing the unexpected changes.
+// As with [DockerContainer.Diff], the changes are relative to the image, so the
+// paths written while the container starts must be allowed too.
+func RequireNoUnexpectedWrites(ctx context.Context, tb testing.TB, ctr *DockerContainer, allowed ...string) {
+	tb.Helper()
+
+	changes, err := ctr.Diff(ctx)
+	require.NoError(tb, err)
+
+	unexpected := changes.Outside(allowed...)
+	require.Emptyf(tb, unexpected, "unexpected filesystem changes outside %v:\n%s", allowed, unexpected)
+}
+
+// RecordStats is a helper function that starts recording the resource usage
+// samples of the container, which stops when the test ends.
+// If dir is not empty, the samples are written as CSV and JSON reports to dir
+// when the test ends, named after the test.
+func RecordStats(tb testing.TB, ctr *DockerContainer, dir string) *StatsSampler {
+	tb.Helper()
+
+	sampler, err := ctr.SampleStats(context.Background())
+	require.NoError(tb, err)
+
+	tb.Cleanup(func() {
+		if err := sampler.Stop(); err != nil {
+			tb.Logf("stats sampling of container %s stopped: %v", ctr.ID[:12], err)
+		}
+
+		if dir != "" {
+			name := strings.ReplaceAll(tb.Name(), "/", "_") + "-" + ctr.ID[:12]
+			require.NoError(tb, sampler.WriteReports(dir, name))
+		}
+	})
+
+	return sampler
+}
+
+// AssertMemoryBelow is a helper function that marks the test as failed if the
+// memory usage recorded by the sampler reached the limit, in bytes.
+// It reports whether the assertion succeeded.
+func AssertMemoryBelow(tb testing.TB, sampler *StatsSampler, limit uint64) bool {
+	tb.Helper()
+
+	peak := sampler.PeakMemory()
+	if peak >= limit {
+		tb.Errorf("peak memory usage %d bytes is not below %d bytes", peak, limit)
+		return false
+	}
+
+	return true
+}
+
+// MetricAssertion describes the metric checked by [AssertMetric].
+type MetricAssertion struct {
+	// Port is the container port serving the metrics, e.g. "9090/tcp".
+	Port string
+
+	// Path is the path of the metrics endpoint, "/metrics" if empty.
+	Path string
+
+	// Name is the name of the metric.
+	Name string
+
+	// Labels are the labels the samples of the metric must have, if any.
+	Labels map[string]string
+
+	// Match is the condition all the samples of the metric must match.
+	// If nil, only the existence of the metric is checked.
+	Match func(value float64) bool
+}
+
+// AssertMetric is a helper function that marks the test as failed if the samples
+// of the metric described by assertion, scraped from the container, don't exist
+// or don't all match. It reports whether the assertion succeeded.
+func AssertMetric(ctx context.Context, tb testing.TB, ctr *DockerContainer, assertion MetricAssertion) bool {
+	tb.Helper()
+
+	path := assertion.Path
+	if path == "" {
+		path = "/metrics"
+	}
+
+	metrics, err := ctr.Metrics(ctx, assertion.Port, path)
+	if err != nil {
+		tb.Errorf("scrape metrics: %v", err)
+		return false
+	}
+
+	found := metrics.Find(assertion.Name, assertion.Labels)
+	if len(found) == 0 {
+		tb.Errorf("metric %s with labels %v not found", assertion.Name, assertion.Labels)
+		return false
+	}
+
+	if assertion.Match == nil {
+		return true
+	}
+
+	ok := true
+	for _, m := range found {
+		if !assertion.Match(m.Value) {
+			tb.Errorf("metric %s doesn't match", m)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// FailOnCrash is a helper function that marks the test as failed when it ends,
+// if the container crashed while the test was running, reporting the failures.
+// The container must have been created with [WithCrashWatchdog].
+func FailOnCrash(tb testing.TB, ctr *DockerContainer) {
+	tb.Helper()
+
+	if ctr.watchdog.Load() == nil {
+		tb.Fatalf("container %s: crash watchdog not enabled, use WithCrashWatchdog", ctr.ID[:12])
+	}
+
+	tb.Cleanup(func() {
+		for _, report := range ctr.Crashes() {
+			tb.Errorf("unexpected container failure: %s", report)
+		}
+	})
+}
@@ -0,0 +1,485 @@
+package tlscert
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/client"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// The paths where WithTLSFrom writes the TLS material in the container.
+const (
+	// CACertPath is the path of the PEM encoded certificate of the CA.
+	CACertPath = "/etc/testcontainers/tls/ca.crt"
+
+	// CertPath is the path of the PEM encoded certificate of the container.
+	CertPath = "/etc/testcontainers/tls/tls.crt"
+
+	// KeyPath is the path of the PEM encoded private key of the container.
+	KeyPath = "/etc/testcontainers/tls/tls.key"
+)
+
+// validity is the validity of the certificates, longer than any test run.
+const validity = 24 * time.Hour
+
+// CA is an ephemeral certificate authority, issuing the certificates of the
+// containers and of their clients for the duration of the tests.
+// It is safe to share a CA between tests and containers.
+type CA struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     crypto.Signer
+}
+
+// NewCA creates a certificate authority with a new self-signed certificate.
+func NewCA() (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate CA key: %w", err)
+	}
+
+	tmpl, err := template("Testcontainers ephemeral CA")
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.IsCA = true
+	tmpl.BasicConstraintsValid = true
+	tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("create CA certificate: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse CA certificate: %w", err)
+	}
+
+	return &CA{
+		cert:    cert,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:     key,
+	}, nil
+}
+
+// Certificate returns the certificate of the CA.
+func (ca *CA) Certificate() *x509.Certificate {
+	return ca.cert
+}
+
+// CertPEM returns the PEM encoded certificate of the CA.
+func (ca *CA) CertPEM() []byte {
+	return ca.certPEM
+}
+
+// CertPool returns a pool holding the certificate of the CA.
+func (ca *CA) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// TLSConfig returns a client configuration trusting the certificates issued by the CA.
+// For mutual TLS, add a certificate issued by the CA to its Certificates.
+func (ca *CA) TLSConfig() *tls.Config {
+	return &tls.Config{
+		RootCAs:    ca.CertPool(),
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+// Issue issues a certificate with the given common name, valid for the subject
+// alternative names, either host names or IP addresses, bracketed or not.
+// The certificate can be used both by servers and by clients.
+func (ca *CA) Issue(commonName string, sans ...string) (*Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	return ca.issue(key, commonName, sans)
+}
+
+// issue issues a certificate for the key, so that a certificate can be issued
+// again with other subject alternative names, but for the same key.
+func (ca *CA) issue(key crypto.Signer, commonName string, sans []string) (*Certificate, error) {
+	tmpl, err := template(commonName)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	addSANs(tmpl, sans)
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("create certificate: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal key: %w", err)
+	}
+
+	return &Certificate{
+		Cert:    cert,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		key:     key,
+	}, nil
+}
+
+// Certificate is a certificate issued by a CA, with its private key.
+type Certificate struct {
+	// Cert is the certificate.
+	Cert *x509.Certificate
+
+	// CertPEM is the PEM encoded certificate.
+	CertPEM []byte
+
+	// KeyPEM is the PEM encoded PKCS #8 private key.
+	KeyPEM []byte
+
+	key crypto.Signer
+}
+
+// TLSCertificate returns the certificate and its key for a [tls.Config].
+func (c *Certificate) TLSCertificate() (tls.Certificate, error) {
+	return tls.X509KeyPair(c.CertPEM, c.KeyPEM)
+}
+
+// template returns the template of a certificate with a random serial number.
+func template(commonName string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Testcontainers"},
+			CommonName:   commonName,
+		},
+		// Tolerate a clock skew between the host and the containers.
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(validity),
+	}, nil
+}
+
+// addSANs adds the subject alternative names to the template, skipping the
+// empty and duplicated ones. An IP address loses its zone, which a
+// certificate can't hold.
+func addSANs(tmpl *x509.Certificate, sans []string) {
+	seen := make(map[string]bool, len(sans))
+	for _, san := range sans {
+		san = strings.Trim(san, "[]")
+		if san == "" {
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(san); err == nil {
+			addr = addr.WithZone("")
+			if !seen[addr.String()] {
+				seen[addr.String()] = true
+				tmpl.IPAddresses = append(tmpl.IPAddresses, net.IP(addr.AsSlice()))
+			}
+			continue
+		}
+
+		if !seen[san] {
+			seen[san] = true
+			tmpl.DNSNames = append(tmpl.DNSNames, san)
+		}
+	}
+}
+
+// options are the options of WithTLSFrom.
+type options struct {
+	sans     []string
+	uid, gid int
+	keyMode  int64
+}
+
+// Option is an option of WithTLSFrom.
+type Option func(*options)
+
+// WithSANs adds subject alternative names to the certificate of the container,
+// either host names or IP addresses.
+func WithSANs(sans ...string) Option {
+	return func(o *options) {
+		o.sans = append(o.sans, sans...)
+	}
+}
+
+// WithOwner sets the user and group IDs owning the files, root by default.
+// Use it for the servers which refuse a private key they don't own, e.g. Postgres.
+func WithOwner(uid, gid int) Option {
+	return func(o *options) {
+		o.uid = uid
+		o.gid = gid
+	}
+}
+
+// WithKeyMode sets the file mode of the private key, 0o600 by default,
+// e.g. 0o640 to let the group of its owner read it.
+func WithKeyMode(mode int64) Option {
+	return func(o *options) {
+		o.keyMode = mode
+	}
+}
+
+// WithTLSFrom issues a certificate from the CA for the container, writing it
+// to CertPath, its key to KeyPath and the certificate of the CA to CACertPath
+// once the container is created, so they're available when it starts.
+// The certificates are readable by every user of the container, and the key by
+// its owner only, root unless set with WithOwner.
+//
+// The subject alternative names of the certificate include localhost, the
+// loopback addresses and the host of the container, to connect from the tests,
+// as well as the name, the hostname, the network aliases and the static IPs of
+// the container, to connect from other containers, plus the ones set with WithSANs.
+//
+// The IPs Docker assigns to the container when it starts can't be known before,
+// so the certificate is issued again with them once the container is started,
+// for the same key. The server only presents it once it reloads its certificate.
+func WithTLSFrom(ca *CA, opts ...Option) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if ca == nil {
+			return errors.New("tls: nil CA")
+		}
+
+		o := options{keyMode: 0o600}
+		for _, opt := range opts {
+			opt(&o)
+		}
+
+		issuer := &containerIssuer{ca: ca, opts: o}
+
+		return testcontainers.WithAdditionalLifecycleHooks(testcontainers.ContainerLifecycleHooks{
+			PostCreates: []testcontainers.ContainerHook{issuer.issue},
+			PostStarts:  []testcontainers.ContainerHook{issuer.reissue},
+		})(req)
+	}
+}
+
+// containerIssuer issues the certificate of a container, and writes it with
+// the TLS material into the container.
+type containerIssuer struct {
+	ca   *CA
+	opts options
+
+	// cert is the last certificate issued for the container.
+	cert *Certificate
+}
+
+// issue issues the certificate of the created container and copies the TLS material into it.
+func (i *containerIssuer) issue(ctx context.Context, c testcontainers.Container) error {
+	sans, err := containerSANs(ctx, c)
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+
+	cert, err := i.ca.Issue(sans[0], append(sans, i.opts.sans...)...)
+	if err != nil {
+		return fmt.Errorf("tls: issue certificate: %w", err)
+	}
+
+	i.cert = cert
+
+	return i.write(ctx, c, []tlsFile{
+		{path: CACertPath, content: i.ca.CertPEM(), mode: 0o644},
+		{path: CertPath, content: cert.CertPEM, mode: 0o644},
+		{path: KeyPath, content: cert.KeyPEM, mode: i.opts.keyMode},
+	})
+}
+
+// reissue issues the certificate of the started container again, for the same key,
+// if Docker assigned it IPs missing from its subject alternative names, and
+// replaces the certificate in the container.
+func (i *containerIssuer) reissue(ctx context.Context, c testcontainers.Container) error {
+	if i.cert == nil {
+		return nil
+	}
+
+	sans, err := containerSANs(ctx, c)
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+
+	sans = append(sans, i.opts.sans...)
+	if hasSANs(i.cert.Cert, sans) {
+		return nil
+	}
+
+	cert, err := i.ca.issue(i.cert.key, sans[0], sans)
+	if err != nil {
+		return fmt.Errorf("tls: issue certificate again: %w", err)
+	}
+
+	i.cert = cert
+
+	return i.write(ctx, c, []tlsFile{
+		{path: CertPath, content: cert.CertPEM, mode: 0o644},
+	})
+}
+
+// write copies the files into the container.
+func (i *containerIssuer) write(ctx context.Context, c testcontainers.Container, files []tlsFile) error {
+	archive, err := tarTLS(i.opts, files)
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+
+	// The files are copied as an archive, the only way to set their owner.
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("tls: docker client: %w", err)
+	}
+	defer cli.Close()
+
+	if _, err = cli.CopyToContainer(ctx, c.GetContainerID(), client.CopyToContainerOptions{
+		DestinationPath: "/",
+		Content:         archive,
+	}); err != nil {
+		return fmt.Errorf("tls: copy to container: %w", err)
+	}
+
+	return nil
+}
+
+// hasSANs returns whether the certificate holds all the subject alternative names.
+func hasSANs(cert *x509.Certificate, sans []string) bool {
+	var tmpl x509.Certificate
+	addSANs(&tmpl, sans)
+
+	for _, name := range tmpl.DNSNames {
+		if !slices.Contains(cert.DNSNames, name) {
+			return false
+		}
+	}
+
+	for _, ip := range tmpl.IPAddresses {
+		if !slices.ContainsFunc(cert.IPAddresses, ip.Equal) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tlsFile is a file of the TLS material.
+type tlsFile struct {
+	path    string
+	content []byte
+	mode    int64
+}
+
+// tarTLS returns an archive of the files, owned by the user and group of the options.
+func tarTLS(o options, files []tlsFile) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	now := time.Now()
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     strings.TrimPrefix(f.path, "/"),
+			Mode:     f.mode,
+			Size:     int64(len(f.content)),
+			Uid:      o.uid,
+			Gid:      o.gid,
+			ModTime:  now,
+		}); err != nil {
+			return nil, fmt.Errorf("write header %s: %w", f.path, err)
+		}
+
+		if _, err := tw.Write(f.content); err != nil {
+			return nil, fmt.Errorf("write %s: %w", f.path, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+
+	return &buf, nil
+}
+
+// containerSANs returns the names and addresses the container is reachable at,
+// its hostname first.
+func containerSANs(ctx context.Context, c testcontainers.Container) ([]string, error) {
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("inspect: %w", err)
+	}
+
+	host, err := c.Host(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("host: %w", err)
+	}
+
+	var sans []string
+	if inspect.Config != nil {
+		sans = append(sans, inspect.Config.Hostname)
+	}
+
+	sans = append(sans, strings.TrimPrefix(inspect.Name, "/"), "localhost", "127.0.0.1", "::1", host)
+
+	if inspect.NetworkSettings != nil {
+		for _, nw := range inspect.NetworkSettings.Networks {
+			sans = append(sans, nw.Aliases...)
+			if nw.IPAMConfig != nil {
+				sans = appendAddrs(sans, nw.IPAMConfig.IPv4Address, nw.IPAMConfig.IPv6Address)
+			}
+
+			// Set when the container was already started.
+			sans = appendAddrs(sans, nw.IPAddress, nw.GlobalIPv6Address)
+		}
+	}
+
+	// The hostname is empty when sharing the network namespace of another container.
+	if sans[0] == "" {
+		sans = sans[1:]
+	}
+
+	return sans, nil
+}
+
+// appendAddrs appends the valid addresses to the subject alternative names.
+func appendAddrs(sans []string, addrs ...netip.Addr) []string {
+	for _, addr := range addrs {
+		if addr.IsValid() {
+			sans = append(sans, addr.String())
+		}
+	}
+
+	return sans
+}
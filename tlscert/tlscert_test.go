@@ -0,0 +1,258 @@
+package tlscert_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/tlscert"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestCA(t *testing.T) {
+	ca, err := tlscert.NewCA()
+	require.NoError(t, err)
+
+	require.True(t, ca.Certificate().IsCA)
+	require.Contains(t, string(ca.CertPEM()), "BEGIN CERTIFICATE")
+
+	t.Run("issue", func(t *testing.T) {
+		cert, err := ca.Issue("server", "localhost", "[::1]", "fe80::1%eth0", "127.0.0.1", "localhost", "")
+		require.NoError(t, err)
+
+		require.Equal(t, "server", cert.Cert.Subject.CommonName)
+		require.Equal(t, []string{"localhost"}, cert.Cert.DNSNames)
+		require.Len(t, cert.Cert.IPAddresses, 3)
+		require.Equal(t, "::1", cert.Cert.IPAddresses[0].String())
+		require.Equal(t, "fe80::1", cert.Cert.IPAddresses[1].String())
+		require.Equal(t, "127.0.0.1", cert.Cert.IPAddresses[2].String())
+
+		for _, name := range []string{"localhost", "::1", "127.0.0.1"} {
+			_, err = cert.Cert.Verify(x509.VerifyOptions{
+				DNSName:   name,
+				Roots:     ca.CertPool(),
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			})
+			require.NoError(t, err, name)
+		}
+
+		_, err = cert.Cert.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: ca.CertPool()})
+		require.Error(t, err)
+	})
+
+	t.Run("other-ca", func(t *testing.T) {
+		other, err := tlscert.NewCA()
+		require.NoError(t, err)
+
+		cert, err := other.Issue("server", "localhost")
+		require.NoError(t, err)
+
+		_, err = cert.Cert.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: ca.CertPool()})
+		require.Error(t, err)
+	})
+
+	t.Run("mutual-tls", func(t *testing.T) {
+		serverCert, err := ca.Issue("server", "localhost")
+		require.NoError(t, err)
+		serverTLS, err := serverCert.TLSCertificate()
+		require.NoError(t, err)
+
+		clientCert, err := ca.Issue("client")
+		require.NoError(t, err)
+		clientTLS, err := clientCert.TLSCertificate()
+		require.NoError(t, err)
+
+		serverConn, clientConn := net.Pipe()
+		defer serverConn.Close()
+		defer clientConn.Close()
+
+		server := tls.Server(serverConn, &tls.Config{
+			Certificates: []tls.Certificate{serverTLS},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    ca.CertPool(),
+			MinVersion:   tls.VersionTLS12,
+		})
+
+		clientConfig := ca.TLSConfig()
+		clientConfig.ServerName = "localhost"
+		clientConfig.Certificates = []tls.Certificate{clientTLS}
+		client := tls.Client(clientConn, clientConfig)
+
+		errs := make(chan error, 1)
+		go func() {
+			errs <- server.Handshake()
+		}()
+
+		require.NoError(t, client.Handshake())
+		require.NoError(t, <-errs)
+		require.Equal(t, "client", server.ConnectionState().PeerCertificates[0].Subject.CommonName)
+	})
+}
+
+func TestWithTLSFrom(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nil-ca", func(t *testing.T) {
+		req := testcontainers.GenericContainerRequest{}
+		require.EqualError(t, tlscert.WithTLSFrom(nil)(&req), "tls: nil CA")
+	})
+
+	t.Run("https", func(t *testing.T) {
+		// withTLSFrom {
+		ca, err := tlscert.NewCA()
+		require.NoError(t, err)
+
+		nw, err := network.New(ctx)
+		testcontainers.CleanupNetwork(t, nw)
+		require.NoError(t, err)
+
+		ctr, err := testcontainers.Run(ctx, "nginx:alpine",
+			testcontainers.WithExposedPorts("443/tcp"),
+			testcontainers.WithFiles(testcontainers.ContainerFile{
+				Reader:            strings.NewReader(nginxTLSConf),
+				ContainerFilePath: "/etc/nginx/conf.d/default.conf",
+				FileMode:          0o644,
+			}),
+			network.WithNetwork([]string{"web"}, nw),
+			tlscert.WithTLSFrom(ca, tlscert.WithSANs("web.example.com")),
+			testcontainers.WithWaitStrategy(wait.ForListeningPort("443/tcp")),
+		)
+		testcontainers.CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		endpoint, err := ctr.PortEndpoint(ctx, "443/tcp", "https")
+		require.NoError(t, err)
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: ca.TLSConfig()}}
+		resp, err := client.Get(endpoint)
+		// }
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, "ok", string(body))
+
+		code, reader, err := ctr.Exec(ctx, []string{"cat", tlscert.CACertPath})
+		require.NoError(t, err)
+		require.Zero(t, code)
+		caPEM, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Contains(t, string(caPEM), string(ca.CertPEM()))
+
+		cert := resp.TLS.PeerCertificates[0]
+		require.Contains(t, cert.DNSNames, "web")
+		require.Contains(t, cert.DNSNames, "web.example.com")
+		require.Contains(t, cert.DNSNames, "localhost")
+
+		// The certificate is issued again with the IPs assigned when the container
+		// started, and presented once nginx reloads it.
+		ips, err := ctr.ContainerIPs(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, ips)
+
+		code, _, err = ctr.Exec(ctx, []string{"nginx", "-s", "reload"})
+		require.NoError(t, err)
+		require.Zero(t, code)
+
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			resp, err := client.Get(endpoint)
+			require.NoError(c, err)
+			defer resp.Body.Close()
+
+			var certIPs []string
+			for _, ip := range resp.TLS.PeerCertificates[0].IPAddresses {
+				certIPs = append(certIPs, ip.String())
+			}
+			for _, ip := range ips {
+				require.Contains(c, certIPs, ip)
+			}
+		}, 10*time.Second, 100*time.Millisecond)
+
+		code, reader, err = ctr.Exec(ctx, []string{"stat", "-c", "%n %a %u:%g", tlscert.CACertPath, tlscert.CertPath, tlscert.KeyPath}, tcexec.Multiplexed())
+		require.NoError(t, err)
+		require.Zero(t, code)
+		modes, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, tlscert.CACertPath+" 644 0:0\n"+tlscert.CertPath+" 644 0:0\n"+tlscert.KeyPath+" 600 0:0\n", string(modes))
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		ca, err := tlscert.NewCA()
+		require.NoError(t, err)
+
+		// withTLSFromOwner {
+		ctr, err := testcontainers.Run(ctx, "postgres:16-alpine",
+			testcontainers.WithExposedPorts("5432/tcp"),
+			testcontainers.WithEnv(map[string]string{"POSTGRES_PASSWORD": "postgres"}),
+			testcontainers.WithCmd("postgres",
+				"-c", "ssl=on",
+				"-c", "ssl_cert_file="+tlscert.CertPath,
+				"-c", "ssl_key_file="+tlscert.KeyPath,
+			),
+			// Postgres refuses a private key it doesn't own, and runs as the user 70 of the alpine image.
+			tlscert.WithTLSFrom(ca, tlscert.WithOwner(70, 70)),
+			testcontainers.WithWaitStrategy(
+				wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+				wait.ForListeningPort("5432/tcp"),
+			),
+		)
+		// }
+		testcontainers.CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		endpoint, err := ctr.PortEndpoint(ctx, "5432/tcp", "")
+		require.NoError(t, err)
+
+		conn, err := net.Dial("tcp", endpoint)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// SSLRequest message: its length and the SSL request code.
+		_, err = conn.Write([]byte{0, 0, 0, 8, 0x04, 0xd2, 0x16, 0x2f})
+		require.NoError(t, err)
+
+		reply := make([]byte, 1)
+		_, err = io.ReadFull(conn, reply)
+		require.NoError(t, err)
+		require.Equal(t, "S", string(reply))
+
+		config := ca.TLSConfig()
+		config.ServerName = "localhost"
+		require.NoError(t, tls.Client(conn, config).Handshake())
+
+		// The clients of the other containers can verify the certificate too.
+		code, reader, err := ctr.Exec(ctx, []string{
+			"psql", "host=localhost user=postgres sslmode=verify-full sslrootcert=" + tlscert.CACertPath,
+			"-tAc", "SELECT ssl FROM pg_stat_ssl WHERE pid = pg_backend_pid()",
+		}, tcexec.WithEnv([]string{"PGPASSWORD=postgres"}), tcexec.Multiplexed())
+		require.NoError(t, err)
+		out, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Zero(t, code, string(out))
+		require.Equal(t, "t\n", string(out))
+	})
+}
+
+const nginxTLSConf = `server {
+    listen 443 ssl;
+    ssl_certificate ` + tlscert.CertPath + `;
+    ssl_certificate_key ` + tlscert.KeyPath + `;
+
+    location / {
+        return 200 'ok';
+    }
+}
+`
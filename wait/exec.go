@@ -111,14 +111,29 @@ func (ws *ExecStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 			if err != nil {
 				return err
 			}
+			output := newExcerptReader(resp)
 			if !ws.ExitCodeMatcher(exitCode) {
+				ws.recordResult(ctx, exitCode, output, fmt.Errorf("exit code: %w", errNotMatched))
 				continue
 			}
-			if ws.ResponseMatcher != nil && !ws.ResponseMatcher(resp) {
+			if ws.ResponseMatcher != nil && !ws.ResponseMatcher(output) {
+				ws.recordResult(ctx, exitCode, output, fmt.Errorf("output: %w", errNotMatched))
 				continue
 			}
 
+			recordAttempt(ctx, ws, Attempt{Exec: &ExecAttempt{ExitCode: exitCode}})
 			return nil
 		}
 	}
 }
+
+// recordResult records the failed attempt with the exit code and an excerpt of the output.
+func (ws *ExecStrategy) recordResult(ctx context.Context, exitCode int, output *excerptReader, err error) {
+	recordAttempt(ctx, ws, Attempt{
+		Err: err,
+		Exec: &ExecAttempt{
+			ExitCode: exitCode,
+			Output:   output.String(),
+		},
+	})
+}
@@ -0,0 +1,370 @@
+package wait
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/moby/moby/api/types/network"
+)
+
+// Implement interface
+var (
+	_ Strategy        = (*GRPCHealthStrategy)(nil)
+	_ StrategyTimeout = (*GRPCHealthStrategy)(nil)
+)
+
+// grpcHealthCheckPath is the path of the Check RPC of the standard gRPC health
+// checking protocol, grpc.health.v1.Health.
+const grpcHealthCheckPath = "/grpc.health.v1.Health/Check"
+
+// GRPCHealthStatus is the serving status of a gRPC health check response.
+type GRPCHealthStatus int
+
+// The serving statuses defined by the health checking protocol.
+const (
+	GRPCHealthStatusUnknown        GRPCHealthStatus = 0
+	GRPCHealthStatusServing        GRPCHealthStatus = 1
+	GRPCHealthStatusNotServing     GRPCHealthStatus = 2
+	GRPCHealthStatusServiceUnknown GRPCHealthStatus = 3 // only reported by the Watch RPC
+)
+
+// String returns the name of the status, as defined by the health checking protocol.
+func (s GRPCHealthStatus) String() string {
+	switch s {
+	case GRPCHealthStatusUnknown:
+		return "UNKNOWN"
+	case GRPCHealthStatusServing:
+		return "SERVING"
+	case GRPCHealthStatusNotServing:
+		return "NOT_SERVING"
+	case GRPCHealthStatusServiceUnknown:
+		return "SERVICE_UNKNOWN"
+	default:
+		return fmt.Sprintf("GRPCHealthStatus(%d)", int(s))
+	}
+}
+
+// GRPCHealthStrategy waits until the grpc.health.v1.Health service of the
+// container reports the SERVING status.
+type GRPCHealthStrategy struct {
+	// all Strategies should have a startupTimeout to avoid waiting infinitely
+	timeout *time.Duration
+
+	// additional properties
+	Port         network.Port
+	Service      string
+	UseTLS       bool
+	TLSConfig    *tls.Config // TLS config for the connection
+	PollInterval time.Duration
+}
+
+// NewGRPCHealthStrategy constructs a gRPC health strategy checking the overall
+// health of the server listening on port.
+func NewGRPCHealthStrategy(port string) *GRPCHealthStrategy {
+	ws := &GRPCHealthStrategy{
+		PollInterval: defaultPollInterval(),
+	}
+	if p, err := network.ParsePort(port); err == nil {
+		ws.Port = p
+	}
+
+	return ws
+}
+
+// ForGRPCHealth is the default construction for the fluid interface.
+//
+// For Example:
+//
+//	wait.
+//		ForGRPCHealth("50051/tcp").
+//		WithService("my.package.MyService")
+func ForGRPCHealth(port string) *GRPCHealthStrategy {
+	return NewGRPCHealthStrategy(port)
+}
+
+// fluent builders for each property
+// since go has neither covariance nor generics, the return type must be the type of the concrete implementation
+// this is true for all properties, even the "shared" ones like startupTimeout
+
+// WithStartupTimeout can be used to change the default startup timeout
+func (ws *GRPCHealthStrategy) WithStartupTimeout(timeout time.Duration) *GRPCHealthStrategy {
+	ws.timeout = &timeout
+	return ws
+}
+
+// WithService sets the name of the service to check.
+// Default is empty, which checks the overall health of the server.
+func (ws *GRPCHealthStrategy) WithService(service string) *GRPCHealthStrategy {
+	ws.Service = service
+	return ws
+}
+
+// WithTLS enables TLS for the connection, using the optional TLS config.
+func (ws *GRPCHealthStrategy) WithTLS(useTLS bool, tlsconf ...*tls.Config) *GRPCHealthStrategy {
+	ws.UseTLS = useTLS
+	if useTLS && len(tlsconf) > 0 {
+		ws.TLSConfig = tlsconf[0]
+	}
+	return ws
+}
+
+// WithPollInterval can be used to override the default polling interval of 100 milliseconds
+func (ws *GRPCHealthStrategy) WithPollInterval(pollInterval time.Duration) *GRPCHealthStrategy {
+	ws.PollInterval = pollInterval
+	return ws
+}
+
+func (ws *GRPCHealthStrategy) Timeout() *time.Duration {
+	return ws.timeout
+}
+
+// String returns a human-readable description of the wait strategy.
+func (ws *GRPCHealthStrategy) String() string {
+	proto := "gRPC"
+	if ws.UseTLS {
+		proto = "gRPC over TLS"
+	}
+
+	service := ""
+	if ws.Service != "" {
+		service = fmt.Sprintf(" of service %q", ws.Service)
+	}
+
+	return fmt.Sprintf("%s health check%s on port %s", proto, service, ws.Port.Port())
+}
+
+// WaitUntilReady implements Strategy.WaitUntilReady
+func (ws *GRPCHealthStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
+	timeout := defaultStartupTimeout()
+	if ws.timeout != nil {
+		timeout = *ws.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if ws.Port.IsZero() {
+		return errors.New("no port to check the gRPC health of")
+	}
+
+	ipAddress, err := target.Host(ctx)
+	if err != nil {
+		return err
+	}
+
+	mappedPort, err := target.MappedPort(ctx, ws.Port.String())
+	for mappedPort.IsZero() {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-time.After(ws.PollInterval):
+			if err := checkTarget(ctx, target); err != nil {
+				return err
+			}
+
+			mappedPort, err = target.MappedPort(ctx, ws.Port.String())
+		}
+	}
+
+	if mappedPort.Proto() != "tcp" {
+		return errors.New("cannot use gRPC client on non-TCP ports")
+	}
+
+	// gRPC runs on HTTP/2, without TLS unless enabled.
+	protocols := new(http.Protocols)
+	if ws.UseTLS {
+		protocols.SetHTTP2(true)
+	} else {
+		protocols.SetUnencryptedHTTP2(true)
+	}
+
+	tripper := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:   true,
+		Protocols:           protocols,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:     ws.TLSConfig,
+	}
+	defer tripper.CloseIdleConnections()
+
+	client := &http.Client{Transport: tripper, Timeout: time.Second}
+
+	endpoint := url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(ipAddress, mappedPort.Port()),
+		Path:   grpcHealthCheckPath,
+	}
+	if ws.UseTLS {
+		endpoint.Scheme = "https"
+	}
+
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Join(lastErr, ctx.Err())
+		case <-time.After(ws.PollInterval):
+			if err := checkTarget(ctx, target); err != nil {
+				return err
+			}
+
+			status, err := grpcHealthCheck(ctx, client, endpoint.String(), ws.Service)
+			if err != nil {
+				lastErr = err
+				recordAttempt(ctx, ws, Attempt{Err: err, GRPC: &GRPCAttempt{Status: status}})
+				continue
+			}
+
+			if status != GRPCHealthStatusServing {
+				lastErr = fmt.Errorf("health status %s", status)
+				recordAttempt(ctx, ws, Attempt{Err: fmt.Errorf("health status: %w", errNotMatched), GRPC: &GRPCAttempt{Status: status}})
+				continue
+			}
+
+			recordAttempt(ctx, ws, Attempt{GRPC: &GRPCAttempt{Status: status}})
+			return nil
+		}
+	}
+}
+
+// grpcHealthCheck calls the Check RPC of the health service at endpoint,
+// returning the status of service.
+func grpcHealthCheck(ctx context.Context, client *http.Client, endpoint string, service string) (GRPCHealthStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(grpcFrame(encodeGRPCHealthCheckRequest(service))))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	// The trailers are only available once the body is read.
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("read response: %w", err)
+	}
+
+	// The status is in the headers of responses without message.
+	code, message := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if code == "" {
+		code, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if code != "0" {
+		return 0, fmt.Errorf("gRPC status %s: %s", code, message)
+	}
+
+	msg, err := parseGRPCFrame(body)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeGRPCHealthCheckResponse(msg)
+}
+
+// grpcFrame prefixes the message with the uncompressed flag and its length,
+// as defined by the gRPC over HTTP/2 protocol.
+func grpcFrame(msg []byte) []byte {
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+
+	return append(frame, msg...)
+}
+
+// parseGRPCFrame returns the message of the length-prefixed frame b.
+func parseGRPCFrame(b []byte) ([]byte, error) {
+	if len(b) < 5 {
+		return nil, fmt.Errorf("short gRPC frame of %d bytes", len(b))
+	}
+
+	if b[0] != 0 {
+		return nil, errors.New("compressed gRPC messages are not supported")
+	}
+
+	n := binary.BigEndian.Uint32(b[1:5])
+	if uint32(len(b)-5) < n {
+		return nil, fmt.Errorf("truncated gRPC message: %d bytes, expected %d", len(b)-5, n)
+	}
+
+	return b[5 : 5+n], nil
+}
+
+// encodeGRPCHealthCheckRequest encodes the HealthCheckRequest protobuf message,
+// whose only field is the service, a string with number 1.
+func encodeGRPCHealthCheckRequest(service string) []byte {
+	if service == "" {
+		return nil
+	}
+
+	msg := []byte{1<<3 | 2} // field 1, length-delimited
+	msg = binary.AppendUvarint(msg, uint64(len(service)))
+
+	return append(msg, service...)
+}
+
+// decodeGRPCHealthCheckResponse decodes the HealthCheckResponse protobuf message,
+// whose only field is the status, an enum with number 1.
+func decodeGRPCHealthCheckResponse(msg []byte) (GRPCHealthStatus, error) {
+	var status GRPCHealthStatus
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return 0, errors.New("invalid protobuf tag")
+		}
+		msg = msg[n:]
+
+		field, wireType := tag>>3, tag&7
+		switch wireType {
+		case 0: // varint
+			v, n := binary.Uvarint(msg)
+			if n <= 0 {
+				return 0, errors.New("invalid protobuf varint")
+			}
+			msg = msg[n:]
+
+			if field == 1 {
+				status = GRPCHealthStatus(v)
+			}
+		case 1: // 64-bit
+			if len(msg) < 8 {
+				return 0, errors.New("truncated protobuf message")
+			}
+			msg = msg[8:]
+		case 2: // length-delimited
+			l, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < l {
+				return 0, errors.New("truncated protobuf message")
+			}
+			msg = msg[n+int(l):]
+		case 5: // 32-bit
+			if len(msg) < 4 {
+				return 0, errors.New("truncated protobuf message")
+			}
+			msg = msg[4:]
+		default:
+			return 0, fmt.Errorf("unsupported protobuf wire type %d", wireType)
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,224 @@
+package wait
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	"github.com/stretchr/testify/require"
+)
+
+// grpcHealthHandler is a fake gRPC health service, reporting the status of the
+// services, which becomes SERVING after the given number of checks.
+func grpcHealthHandler(servingAfter int32, services ...string) http.Handler {
+	var checks atomic.Int32
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != grpcHealthCheckPath || r.Header.Get("Content-Type") != "application/grpc" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		msg, err := parseGRPCFrame(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The request has a single field, so skip its tag and length.
+		var service string
+		if len(msg) > 0 {
+			service = string(msg[2:])
+		}
+
+		w.Header().Set("Content-Type", "application/grpc")
+
+		known := service == ""
+		for _, s := range services {
+			known = known || s == service
+		}
+		if !known {
+			// Trailers-only response.
+			w.Header().Set("Grpc-Status", "5")
+			w.Header().Set("Grpc-Message", "unknown service")
+			return
+		}
+
+		status := GRPCHealthStatusNotServing
+		if checks.Add(1) > servingAfter {
+			status = GRPCHealthStatusServing
+		}
+
+		// Field 1, varint.
+		_, _ = w.Write(grpcFrame([]byte{1 << 3, byte(status)}))
+		w.Header().Set(http.TrailerPrefix+"Grpc-Status", "0")
+	})
+}
+
+// addrTarget returns a running target whose ports are all mapped to the address addr.
+func addrTarget(t *testing.T, addr string) *MockStrategyTarget {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	return &MockStrategyTarget{
+		HostImpl: func(_ context.Context) (string, error) {
+			return host, nil
+		},
+		MappedPortImpl: func(_ context.Context, _ string) (network.Port, error) {
+			return network.ParsePort(port + "/tcp")
+		},
+		StateImpl: func(_ context.Context) (*container.State, error) {
+			return &container.State{Running: true}, nil
+		},
+	}
+}
+
+// startH2CServer starts a server of unencrypted HTTP/2 requests.
+func startH2CServer(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	srv := &http.Server{Handler: handler, Protocols: protocols}
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, srv.Close())
+	})
+
+	return listener.Addr().String()
+}
+
+func TestGRPCHealthStrategy(t *testing.T) {
+	t.Run("serving", func(t *testing.T) {
+		addr := startH2CServer(t, grpcHealthHandler(2))
+
+		err := ForGRPCHealth("50051/tcp").
+			WithStartupTimeout(5*time.Second).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), addrTarget(t, addr))
+		require.NoError(t, err)
+	})
+
+	t.Run("service", func(t *testing.T) {
+		addr := startH2CServer(t, grpcHealthHandler(0, "my.Service"))
+
+		err := ForGRPCHealth("50051/tcp").
+			WithService("my.Service").
+			WithStartupTimeout(5*time.Second).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), addrTarget(t, addr))
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown-service", func(t *testing.T) {
+		addr := startH2CServer(t, grpcHealthHandler(0))
+
+		err := ForGRPCHealth("50051/tcp").
+			WithService("other.Service").
+			WithStartupTimeout(500*time.Millisecond).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), addrTarget(t, addr))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "gRPC status 5: unknown service")
+	})
+
+	t.Run("not-serving", func(t *testing.T) {
+		addr := startH2CServer(t, grpcHealthHandler(1000))
+
+		err := ForGRPCHealth("50051/tcp").
+			WithStartupTimeout(500*time.Millisecond).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), addrTarget(t, addr))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "health status NOT_SERVING")
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		srv := httptest.NewUnstartedServer(grpcHealthHandler(0))
+		srv.EnableHTTP2 = true
+		srv.StartTLS()
+		t.Cleanup(srv.Close)
+
+		pool := x509.NewCertPool()
+		pool.AddCert(srv.Certificate())
+
+		err := ForGRPCHealth("50051/tcp").
+			WithTLS(true, &tls.Config{RootCAs: pool}).
+			WithStartupTimeout(5*time.Second).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), addrTarget(t, srv.Listener.Addr().String()))
+		require.NoError(t, err)
+	})
+
+	t.Run("exited", func(t *testing.T) {
+		addr := startH2CServer(t, grpcHealthHandler(1000))
+		target := addrTarget(t, addr)
+		target.StateImpl = func(_ context.Context) (*container.State, error) {
+			return &container.State{Status: container.StateExited, ExitCode: 1}, nil
+		}
+
+		err := ForGRPCHealth("50051/tcp").
+			WithStartupTimeout(5*time.Second).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.EqualError(t, err, "container exited with code 1")
+	})
+}
+
+func TestGRPCHealthStrategyString(t *testing.T) {
+	require.Equal(t, "gRPC health check on port 50051", ForGRPCHealth("50051/tcp").String())
+	require.Equal(t, `gRPC over TLS health check of service "my.Service" on port 50051`,
+		ForGRPCHealth("50051").WithService("my.Service").WithTLS(true).String())
+}
+
+func TestGRPCHealthCheckMessages(t *testing.T) {
+	require.Empty(t, encodeGRPCHealthCheckRequest(""))
+	require.Equal(t, append([]byte{0x0a, 10}, "my.Service"...), encodeGRPCHealthCheckRequest("my.Service"))
+
+	frame := grpcFrame([]byte{0x08, 0x01})
+	require.Equal(t, []byte{0, 0, 0, 0, 2, 0x08, 0x01}, frame)
+
+	msg, err := parseGRPCFrame(frame)
+	require.NoError(t, err)
+
+	status, err := decodeGRPCHealthCheckResponse(msg)
+	require.NoError(t, err)
+	require.Equal(t, GRPCHealthStatusServing, status)
+
+	// An empty message has the default status, and unknown fields are skipped.
+	status, err = decodeGRPCHealthCheckResponse(nil)
+	require.NoError(t, err)
+	require.Equal(t, GRPCHealthStatusUnknown, status)
+
+	status, err = decodeGRPCHealthCheckResponse([]byte{0x12, 0x01, 'x', 0x08, 0x02})
+	require.NoError(t, err)
+	require.Equal(t, GRPCHealthStatusNotServing, status)
+
+	_, err = parseGRPCFrame([]byte{0, 0, 0, 0, 5, 1})
+	require.Error(t, err)
+
+	require.Equal(t, "GRPCHealthStatus(7)", GRPCHealthStatus(7).String())
+	require.Equal(t, "SERVING", fmt.Sprint(GRPCHealthStatusServing))
+	require.Equal(t, []byte{0, 0, 0, 0, 0}, grpcFrame(nil))
+}
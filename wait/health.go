@@ -2,6 +2,8 @@ package wait
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/moby/moby/api/types/container"
@@ -75,10 +77,11 @@ func (ws *HealthStrategy) WaitUntilReady(ctx context.Context, target StrategyTar
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	var health *container.Health
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return fmt.Errorf("%w: %s", ctx.Err(), describeHealth(health))
 		default:
 			state, err := target.State(ctx)
 			if err != nil {
@@ -87,11 +90,37 @@ func (ws *HealthStrategy) WaitUntilReady(ctx context.Context, target StrategyTar
 			if err := checkState(state); err != nil {
 				return err
 			}
-			if state.Health == nil || state.Health.Status != container.Healthy {
+
+			health = state.Health
+
+			attempt := Attempt{Health: newHealthAttempt(health)}
+			if health == nil || health.Status != container.Healthy {
+				attempt.Err = errors.New(describeHealth(health))
+				recordAttempt(ctx, ws, attempt)
 				time.Sleep(ws.PollInterval)
 				continue
 			}
+
+			recordAttempt(ctx, ws, attempt)
 			return nil
 		}
 	}
 }
+
+// describeHealth returns a human-readable description of the health of the
+// container, including the output of the last healthcheck.
+func describeHealth(health *container.Health) string {
+	if health == nil {
+		// The health is not reported until the first healthcheck starts,
+		// or if the container has no healthcheck.
+		return "container health not reported yet, or no healthcheck configured"
+	}
+
+	desc := fmt.Sprintf("container health status %q", health.Status)
+	if n := len(health.Log); n > 0 && health.Log[n-1] != nil {
+		attempt := newHealthAttempt(health)
+		desc += fmt.Sprintf(", last healthcheck exit code %d, output %q", attempt.ExitCode, attempt.Output)
+	}
+
+	return desc
+}
@@ -82,6 +82,7 @@ func TestWaitFailsForNilHealth(t *testing.T) {
 	err := wg.WaitUntilReady(context.Background(), target)
 	require.Error(t, err)
 	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.EqualError(t, err, "context deadline exceeded: container health not reported yet, or no healthcheck configured")
 }
 
 func TestWaitForHealthFailsDueToOOMKilledContainer(t *testing.T) {
@@ -126,3 +127,41 @@ func TestWaitForHealthFailsDueToUnexpectedContainerStatus(t *testing.T) {
 	require.Error(t, err)
 	require.EqualError(t, err, "unexpected container status \"dead\"")
 }
+
+// TestWaitForHealthReportsLastHealthcheck ensures that the output of the last healthcheck
+// is reported when the container doesn't become healthy.
+func TestWaitForHealthReportsLastHealthcheck(t *testing.T) {
+	target := newStateTarget(t, &container.State{
+		Running: true,
+		Health: &container.Health{
+			Status: container.Unhealthy,
+			Log: []*container.HealthcheckResult{
+				{ExitCode: 1, Output: "connection refused"},
+				{ExitCode: 2, Output: "database is starting up"},
+			},
+		},
+	})
+
+	wg := wait.NewHealthStrategy().WithStartupTimeout(100 * time.Millisecond)
+	err := wait.WaitUntilReady(context.Background(), wg, target)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.EqualError(t, err, `context deadline exceeded: container health status "unhealthy", last healthcheck exit code 2, output "database is starting up"`)
+
+	var waitErr *wait.WaitError
+	require.ErrorAs(t, err, &waitErr)
+	require.Len(t, waitErr.Attempts, 1)
+	require.Equal(t, &wait.HealthAttempt{Status: container.Unhealthy, ExitCode: 2, Output: "database is starting up"}, waitErr.Attempts[0].Health)
+	require.Contains(t, waitErr.Summary(), "container state: unknown, health: unhealthy")
+}
+
+// TestWaitFailsForNilHealthReportsNoHealthcheck ensures that a container without
+// healthcheck is reported as such.
+func TestWaitFailsForNilHealthReportsNoHealthcheck(t *testing.T) {
+	target := newStateTarget(t, &container.State{
+		Running: true,
+	})
+
+	wg := wait.NewHealthStrategy().WithStartupTimeout(100 * time.Millisecond)
+	err := wg.WaitUntilReady(context.Background(), target)
+	require.EqualError(t, err, "context deadline exceeded: container health not reported yet, or no healthcheck configured")
+}
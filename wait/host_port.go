@@ -46,6 +46,13 @@ type HostPortStrategy struct {
 	// skipInternalCheck, makes strategy waiting only for port mapping completion
 	// without accessing port.
 	skipExternalCheck bool
+
+	// UDPProbe is the payload sent to a UDP port by the external check.
+	// As UDP is connectionless, UDP ports are only checked externally if it's set.
+	UDPProbe []byte
+
+	// UDPResponseMatcher checks the response to the UDP probe, any response if nil.
+	UDPResponseMatcher func(response []byte) bool
 }
 
 // NewHostPortStrategy constructs a default host port strategy that waits for the given
@@ -98,6 +105,16 @@ func (hp *HostPortStrategy) SkipExternalCheck() *HostPortStrategy {
 	return hp
 }
 
+// WithUDPProbe sets the payload sent to a UDP port by the external check, which
+// succeeds when a response matching the matcher is received, any response if
+// the matcher is nil. Without a probe, UDP ports are only checked internally.
+func (hp *HostPortStrategy) WithUDPProbe(payload []byte, matcher func(response []byte) bool) *HostPortStrategy {
+	hp.UDPProbe = payload
+	hp.UDPResponseMatcher = matcher
+
+	return hp
+}
+
 // WithStartupTimeout can be used to change the default startup timeout
 func (hp *HostPortStrategy) WithStartupTimeout(startupTimeout time.Duration) *HostPortStrategy {
 	hp.timeout = &startupTimeout
@@ -202,11 +219,16 @@ func (hp *HostPortStrategy) WaitUntilReady(ctx context.Context, target StrategyT
 		}
 	}
 
+	attempt := PortAttempt{Port: internalPort.String()}
+
 	port, err := target.MappedPort(ctx, internalPort.String())
 	i = 0
 
 	for port.IsZero() {
 		i++
+		if err != nil {
+			hp.recordPort(ctx, attempt, err)
+		}
 
 		select {
 		case <-ctx.Done():
@@ -222,28 +244,53 @@ func (hp *HostPortStrategy) WaitUntilReady(ctx context.Context, target StrategyT
 		}
 	}
 
+	attempt.MappedPort = port.String()
 	if !hp.skipExternalCheck {
 		ipAddress, err := target.Host(ctx)
 		if err != nil {
+			hp.recordPort(ctx, attempt, err)
 			return fmt.Errorf("host: %w", err)
 		}
 
-		if err := externalCheck(ctx, ipAddress, port, target, waitInterval); err != nil {
+		report := func(err error) {
+			attempt.External = checkResult(err)
+			hp.recordPort(ctx, attempt, err)
+		}
+
+		switch {
+		case port.Proto() != network.UDP:
+			err = externalCheck(ctx, ipAddress, port, target, waitInterval, report)
+		case hp.UDPProbe != nil:
+			err = externalUDPCheck(ctx, ipAddress, port, target, waitInterval, hp.UDPProbe, hp.UDPResponseMatcher, report)
+		default:
+			// Nothing can tell an open UDP port without a probe.
+			attempt.External = "skipped, no UDP probe"
+		}
+		if err != nil {
 			return fmt.Errorf("external check: %w", err)
 		}
 	}
 
 	if hp.skipInternalCheck {
+		hp.recordPort(ctx, attempt, nil)
 		return nil
 	}
 
-	if err = internalCheck(ctx, internalPort, target); err != nil {
+	err = internalCheck(ctx, internalPort, target, func(err error) {
+		attempt.Internal = checkResult(err)
+		hp.recordPort(ctx, attempt, err)
+	})
+	if err != nil {
 		switch {
 		case errors.Is(err, errShellNotExecutable):
 			log.Printf("Shell not executable in container, only external port validated")
+			attempt.Internal = "skipped, shell not executable"
+			hp.recordPort(ctx, attempt, nil)
 			return nil
 		case errors.Is(err, errShellNotFound):
 			log.Printf("Shell not found in container")
+			attempt.Internal = "skipped, shell not found"
+			hp.recordPort(ctx, attempt, nil)
 			return nil
 		default:
 			return fmt.Errorf("internal check: %w", err)
@@ -253,7 +300,23 @@ func (hp *HostPortStrategy) WaitUntilReady(ctx context.Context, target StrategyT
 	return nil
 }
 
-func externalCheck(ctx context.Context, ipAddress string, port network.Port, target StrategyTarget, waitInterval time.Duration) error {
+// recordPort records the attempt with the results of the port checks.
+func (hp *HostPortStrategy) recordPort(ctx context.Context, attempt PortAttempt, err error) {
+	recordAttempt(ctx, hp, Attempt{Err: err, Port: &attempt})
+}
+
+// checkResult returns the result of a port check to record in a [PortAttempt].
+func checkResult(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+
+	return "listening"
+}
+
+// externalCheck dials the port from the host until it's listening,
+// calling report with the result of each dial.
+func externalCheck(ctx context.Context, ipAddress string, port network.Port, target StrategyTarget, waitInterval time.Duration, report func(error)) error {
 	proto := port.Proto()
 
 	dialer := net.Dialer{}
@@ -263,6 +326,10 @@ func externalCheck(ctx context.Context, ipAddress string, port network.Port, tar
 			return fmt.Errorf("check target: retries: %d address: %s: %w", i, address, err)
 		}
 		conn, err := dialer.DialContext(ctx, string(proto), address)
+		if ctx.Err() == nil {
+			// Don't report the dials cancelled by the context, they're not results.
+			report(err)
+		}
 		if err != nil {
 			var v *net.OpError
 			if errors.As(err, &v) {
@@ -282,8 +349,83 @@ func externalCheck(ctx context.Context, ipAddress string, port network.Port, tar
 	}
 }
 
-func internalCheck(ctx context.Context, internalPort network.Port, target StrategyTarget) error {
+// externalUDPCheck sends the probe to the UDP port from the host until a
+// response matching the matcher is received, calling report with the result
+// of each probe.
+func externalUDPCheck(ctx context.Context, ipAddress string, port network.Port, target StrategyTarget, waitInterval time.Duration, probe []byte, matcher func([]byte) bool, report func(error)) error {
+	dialer := net.Dialer{}
+	address := net.JoinHostPort(ipAddress, port.Port())
+	buf := make([]byte, 65535)
+	for i := 0; ; i++ {
+		if err := checkTarget(ctx, target); err != nil {
+			return fmt.Errorf("check target: retries: %d address: %s: %w", i, address, err)
+		}
+
+		err := probeUDP(ctx, &dialer, address, probe, matcher, buf, max(waitInterval, time.Second))
+		if ctx.Err() == nil {
+			// Don't report the probes cancelled by the context, they're not results.
+			report(err)
+		}
+		if err == nil {
+			return nil
+		}
+
+		var errPermanent *PermanentError
+		if errors.As(err, &errPermanent) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("retries: %d address: %s: %w", i, address, errors.Join(err, ctx.Err()))
+		case <-time.After(waitInterval):
+		}
+	}
+}
+
+// probeUDP sends the probe to the address and waits for a response
+// for the given time, checking it with the matcher.
+func probeUDP(ctx context.Context, dialer *net.Dialer, address string, probe []byte, matcher func([]byte) bool, buf []byte, wait time.Duration) error {
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return NewPermanentError(fmt.Errorf("dial: %w", err))
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(wait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err = conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("set deadline: %w", err)
+	}
+
+	if _, err = conn.Write(probe); err != nil {
+		return fmt.Errorf("write probe: %w", err)
+	}
+
+	// A closed port is reported as a refused connection on read, by an ICMP
+	// port unreachable message.
+	n, err := conn.Read(buf)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if matcher != nil && !matcher(buf[:n]) {
+		return fmt.Errorf("response %q: %w", buf[:n], errNotMatched)
+	}
+
+	return nil
+}
+
+// internalCheck checks the port is listening inside the container,
+// calling report with the result of each check.
+func internalCheck(ctx context.Context, internalPort network.Port, target StrategyTarget, report func(error)) error {
 	command := buildInternalCheckCommand(internalPort.Num())
+	if internalPort.Proto() == network.UDP {
+		command = buildInternalUDPCheckCommand(internalPort.Num())
+	}
 	for {
 		if ctx.Err() != nil {
 			return ctx.Err()
@@ -301,12 +443,15 @@ func internalCheck(ctx context.Context, internalPort network.Port, target Strate
 		// Handle both to ensure compatibility with Docker and Podman for now.
 		switch exitCode {
 		case 0:
+			report(nil)
 			return nil
 		case exitEaccess:
 			return errShellNotExecutable
 		case exitCmdNotFound:
 			return errShellNotFound
 		}
+
+		report(fmt.Errorf("exit code %d", exitCode))
 	}
 }
 
@@ -319,3 +464,9 @@ func buildInternalCheckCommand(internalPort uint16) string {
 				`
 	return "true && " + fmt.Sprintf(command, internalPort, internalPort, internalPort)
 }
+
+// buildInternalUDPCheckCommand builds the command checking a UDP socket is bound
+// to the port, as there is no connection to open.
+func buildInternalUDPCheckCommand(internalPort uint16) string {
+	return fmt.Sprintf("true && cat /proc/net/udp* | awk '{print $2}' | grep -i :%04x", internalPort)
+}
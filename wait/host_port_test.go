@@ -8,6 +8,7 @@ import (
 	"log"
 	"net"
 	"net/netip"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -628,3 +629,156 @@ func TestHostPortStrategySucceedsGivenShellIsNotFound(t *testing.T) {
 
 	require.Contains(t, buf.String(), "Shell not found in container")
 }
+
+// startUDPEchoServer starts a UDP server replying to each packet with
+// the reply, returning its port.
+func startUDPEchoServer(t *testing.T, reply func(packet []byte) []byte) network.Port {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := reply(buf[:n]); resp != nil {
+				_, _ = conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+
+	rawPort := conn.LocalAddr().(*net.UDPAddr).Port
+	port, err := network.ParsePort(fmt.Sprintf("%d/udp", rawPort))
+	require.NoError(t, err)
+
+	return port
+}
+
+// udpTarget returns a running target mapping any port to the given port,
+// recording the commands run in the container.
+func udpTarget(port network.Port, cmds *[]string) *MockStrategyTarget {
+	return &MockStrategyTarget{
+		HostImpl: func(_ context.Context) (string, error) {
+			return "127.0.0.1", nil
+		},
+		MappedPortImpl: func(_ context.Context, _ string) (network.Port, error) {
+			return port, nil
+		},
+		StateImpl: func(_ context.Context) (*container.State, error) {
+			return &container.State{
+				Running: true,
+			}, nil
+		},
+		ExecImpl: func(_ context.Context, cmd []string, _ ...exec.ProcessOption) (int, io.Reader, error) {
+			*cmds = append(*cmds, cmd[len(cmd)-1])
+			return 0, nil, nil
+		},
+	}
+}
+
+func TestHostPortStrategyUDP(t *testing.T) {
+	t.Run("probe", func(t *testing.T) {
+		var probes atomic.Int32
+		port := startUDPEchoServer(t, func(packet []byte) []byte {
+			if probes.Add(1) < 3 {
+				return []byte("starting")
+			}
+			return append([]byte("pong:"), packet...)
+		})
+
+		var cmds []string
+		wg := ForListeningPort("53/udp").
+			WithUDPProbe([]byte("ping"), func(response []byte) bool {
+				return bytes.Equal(response, []byte("pong:ping"))
+			}).
+			WithStartupTimeout(5 * time.Second).
+			WithPollInterval(10 * time.Millisecond)
+
+		err := wg.WaitUntilReady(context.Background(), udpTarget(port, &cmds))
+		require.NoError(t, err)
+		require.Equal(t, int32(3), probes.Load())
+
+		require.Len(t, cmds, 1)
+		require.Contains(t, cmds[0], "/proc/net/udp*")
+		require.Contains(t, cmds[0], ":0035")
+		require.NotContains(t, cmds[0], "/proc/net/tcp")
+	})
+
+	t.Run("any-response", func(t *testing.T) {
+		port := startUDPEchoServer(t, func(packet []byte) []byte {
+			return packet
+		})
+
+		var cmds []string
+		wg := ForListeningPort("53/udp").
+			WithUDPProbe([]byte("ping"), nil).
+			SkipInternalCheck().
+			WithStartupTimeout(5 * time.Second).
+			WithPollInterval(10 * time.Millisecond)
+
+		err := wg.WaitUntilReady(context.Background(), udpTarget(port, &cmds))
+		require.NoError(t, err)
+		require.Empty(t, cmds)
+	})
+
+	t.Run("no-response", func(t *testing.T) {
+		port := startUDPEchoServer(t, func(_ []byte) []byte {
+			return nil
+		})
+
+		var cmds []string
+		wg := ForListeningPort("53/udp").
+			WithUDPProbe([]byte("ping"), nil).
+			WithStartupTimeout(1500 * time.Millisecond).
+			WithPollInterval(10 * time.Millisecond)
+
+		err := WaitUntilReady(context.Background(), wg, udpTarget(port, &cmds))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Empty(t, cmds)
+
+		var waitErr *WaitError
+		require.ErrorAs(t, err, &waitErr)
+		require.Len(t, waitErr.Attempts, 1)
+		require.Equal(t, port.String(), waitErr.Attempts[0].Port.MappedPort)
+	})
+
+	t.Run("closed-port", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port, err := network.ParsePort(fmt.Sprintf("%d/udp", conn.LocalAddr().(*net.UDPAddr).Port))
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+
+		var cmds []string
+		wg := ForListeningPort("53/udp").
+			WithUDPProbe([]byte("ping"), nil).
+			WithStartupTimeout(300 * time.Millisecond).
+			WithPollInterval(10 * time.Millisecond)
+
+		err = WaitUntilReady(context.Background(), wg, udpTarget(port, &cmds))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		var waitErr *WaitError
+		require.ErrorAs(t, err, &waitErr)
+		require.Len(t, waitErr.Attempts, 1)
+		require.Contains(t, waitErr.Attempts[0].Port.External, "refused")
+	})
+
+	t.Run("internal-only", func(t *testing.T) {
+		var cmds []string
+		wg := ForListeningPort("8125/udp").
+			WithStartupTimeout(5 * time.Second).
+			WithPollInterval(10 * time.Millisecond)
+
+		err := WaitUntilReady(context.Background(), wg, udpTarget(network.MustParsePort("32768/udp"), &cmds))
+		require.NoError(t, err)
+
+		require.Len(t, cmds, 1)
+		require.Equal(t, "true && cat /proc/net/udp* | awk '{print $2}' | grep -i :1fbd", cmds[0])
+	})
+}
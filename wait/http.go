@@ -10,7 +10,6 @@ import (
 	"net"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
 	"time"
 
@@ -213,7 +212,6 @@ func (ws *HTTPStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 
 		// Find the lowest numbered exposed tcp port.
 		var lowestPort network.Port
-		var hostPort string
 		for port, bindings := range inspect.NetworkSettings.Ports {
 			if len(bindings) == 0 || port.Proto() != "tcp" {
 				continue
@@ -221,7 +219,6 @@ func (ws *HTTPStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 
 			if lowestPort.IsZero() || port.Num() < lowestPort.Num() {
 				lowestPort = port
-				hostPort = bindings[0].HostPort
 			}
 		}
 
@@ -229,8 +226,12 @@ func (ws *HTTPStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 			return errors.New("no exposed tcp ports or mapped ports - cannot wait for status")
 		}
 
-		hPort, _ := strconv.ParseUint(hostPort, 10, 16)
-		mappedPort, _ = network.PortFrom(uint16(hPort), lowestPort.Proto())
+		// MappedPort picks the binding of the host address family, as the IPv4
+		// and IPv6 bindings of a port may get different host ports.
+		mappedPort, err = target.MappedPort(ctx, lowestPort.String())
+		if err != nil {
+			return err
+		}
 	} else {
 		// Specific port requested; use MappedPort to resolve it.
 		mappedPort, err = target.MappedPort(ctx, ws.Port.String())
@@ -334,24 +335,42 @@ func (ws *HTTPStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 
 			resp, err := client.Do(req)
 			if err != nil {
+				recordAttempt(ctx, ws, Attempt{Err: err, HTTP: &HTTPAttempt{}})
 				continue
 			}
+			body := newExcerptReader(resp.Body)
 			if ws.StatusCodeMatcher != nil && !ws.StatusCodeMatcher(resp.StatusCode) {
+				ws.recordResponse(ctx, resp, body, fmt.Errorf("status code %d: %w", resp.StatusCode, errNotMatched))
 				_ = resp.Body.Close()
 				continue
 			}
-			if ws.ResponseMatcher != nil && !ws.ResponseMatcher(resp.Body) {
+			if ws.ResponseMatcher != nil && !ws.ResponseMatcher(body) {
+				ws.recordResponse(ctx, resp, body, fmt.Errorf("response body: %w", errNotMatched))
 				_ = resp.Body.Close()
 				continue
 			}
 			if ws.ResponseHeadersMatcher != nil && !ws.ResponseHeadersMatcher(resp.Header) {
+				ws.recordResponse(ctx, resp, body, fmt.Errorf("response headers: %w", errNotMatched))
 				_ = resp.Body.Close()
 				continue
 			}
 			if err := resp.Body.Close(); err != nil {
+				recordAttempt(ctx, ws, Attempt{Err: fmt.Errorf("close body: %w", err), HTTP: &HTTPAttempt{StatusCode: resp.StatusCode}})
 				continue
 			}
+			recordAttempt(ctx, ws, Attempt{HTTP: &HTTPAttempt{StatusCode: resp.StatusCode}})
 			return nil
 		}
 	}
 }
+
+// recordResponse records the failed attempt with the response and an excerpt of its body.
+func (ws *HTTPStrategy) recordResponse(ctx context.Context, resp *http.Response, body *excerptReader, err error) {
+	recordAttempt(ctx, ws, Attempt{
+		Err: err,
+		HTTP: &HTTPAttempt{
+			StatusCode: resp.StatusCode,
+			Body:       body.String(),
+		},
+	})
+}
@@ -43,7 +43,7 @@ type LogStrategy struct {
 	PollInterval time.Duration
 
 	// check is the function that will be called to check if the log entry is present.
-	check func([]byte) error
+	check func([]byte) (int, error)
 
 	// submatchCallback is a callback that will be called with the sub matches of the regexp.
 	submatchCallback func(pattern string, matches [][][]byte) error
@@ -188,7 +188,10 @@ func (ws *LogStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget
 				return checkErr
 			}
 
-			if err := ws.check(b); err != nil {
+			matches, err := ws.check(b)
+			if err != nil {
+				recordAttempt(ctx, ws, Attempt{Err: err, Log: &LogAttempt{Matches: matches, Occurrence: ws.Occurrence}})
+
 				var errPermanent *PermanentError
 				if errors.As(err, &errPermanent) {
 					return err
@@ -200,30 +203,37 @@ func (ws *LogStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget
 				continue
 			}
 
+			recordAttempt(ctx, ws, Attempt{Log: &LogAttempt{Matches: matches, Occurrence: ws.Occurrence}})
 			return nil
 		}
 	}
 }
 
-// checkCount checks if the log entry is present in the logs using a string count.
-func (ws *LogStrategy) checkCount(b []byte) error {
-	if count := bytes.Count(b, ws.log); count < ws.Occurrence {
-		return fmt.Errorf("%q matched %d times, expected %d", ws.Log, count, ws.Occurrence)
+// checkCount checks if the log entry is present in the logs using a string count,
+// returning the number of matches.
+func (ws *LogStrategy) checkCount(b []byte) (int, error) {
+	count := bytes.Count(b, ws.log)
+	if count < ws.Occurrence {
+		return count, fmt.Errorf("%q matched %d times, expected %d", ws.Log, count, ws.Occurrence)
 	}
 
-	return nil
+	return count, nil
 }
 
-// checkRegexp checks if the log entry is present in the logs using a regexp count.
-func (ws *LogStrategy) checkRegexp(b []byte) error {
-	if matches := ws.re.FindAll(b, -1); len(matches) < ws.Occurrence {
-		return fmt.Errorf("`%s` matched %d times, expected %d", ws.Log, len(matches), ws.Occurrence)
+// checkRegexp checks if the log entry is present in the logs using a regexp count,
+// returning the number of matches.
+func (ws *LogStrategy) checkRegexp(b []byte) (int, error) {
+	count := len(ws.re.FindAll(b, -1))
+	if count < ws.Occurrence {
+		return count, fmt.Errorf("`%s` matched %d times, expected %d", ws.Log, count, ws.Occurrence)
 	}
 
-	return nil
+	return count, nil
 }
 
-// checkSubmatch checks if the log entry is present in the logs using a regexp sub match callback.
-func (ws *LogStrategy) checkSubmatch(b []byte) error {
-	return ws.submatchCallback(ws.Log, ws.re.FindAllSubmatch(b, -1))
+// checkSubmatch checks if the log entry is present in the logs using a regexp sub match callback,
+// returning the number of matches.
+func (ws *LogStrategy) checkSubmatch(b []byte) (int, error) {
+	matches := ws.re.FindAllSubmatch(b, -1)
+	return len(matches), ws.submatchCallback(ws.Log, matches)
 }
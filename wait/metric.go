@@ -0,0 +1,436 @@
+package wait
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/api/types/network"
+)
+
+// Implement interface
+var (
+	_ Strategy        = (*MetricStrategy)(nil)
+	_ StrategyTimeout = (*MetricStrategy)(nil)
+)
+
+// Metric is a sample of the Prometheus text exposition format.
+type Metric struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// String returns the sample in the text exposition format.
+func (m Metric) String() string {
+	var sb strings.Builder
+	sb.WriteString(m.Name)
+
+	if len(m.Labels) > 0 {
+		sb.WriteByte('{')
+		for i, name := range slices.Sorted(maps.Keys(m.Labels)) {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			fmt.Fprintf(&sb, "%s=%q", name, m.Labels[name])
+		}
+		sb.WriteByte('}')
+	}
+
+	fmt.Fprintf(&sb, " %s", strconv.FormatFloat(m.Value, 'g', -1, 64))
+
+	return sb.String()
+}
+
+// selector returns the name and labels of the sample.
+func (m Metric) selector() string {
+	s, _, _ := strings.Cut(m.String(), " ")
+	return s
+}
+
+// Metrics are the samples scraped from a metrics endpoint.
+type Metrics []Metric
+
+// Find returns the samples with the given name having all the given labels.
+func (ms Metrics) Find(name string, labels map[string]string) Metrics {
+	var found Metrics
+	for _, m := range ms {
+		if m.Name != name {
+			continue
+		}
+
+		matches := true
+		for k, v := range labels {
+			if lv, ok := m.Labels[k]; !ok || lv != v {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			found = append(found, m)
+		}
+	}
+
+	return found
+}
+
+// Value returns the value of the first sample with the given name having all
+// the given labels, and whether such a sample exists.
+func (ms Metrics) Value(name string, labels map[string]string) (float64, bool) {
+	found := ms.Find(name, labels)
+	if len(found) == 0 {
+		return 0, false
+	}
+
+	return found[0].Value, true
+}
+
+// ParseMetrics parses the samples of the Prometheus text exposition format,
+// ignoring the comments, including the HELP and TYPE metadata, and the timestamps.
+func ParseMetrics(r io.Reader) (Metrics, error) {
+	var metrics Metrics
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		m, err := parseMetricLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+
+		metrics = append(metrics, m)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read metrics: %w", err)
+	}
+
+	return metrics, nil
+}
+
+// parseMetricLine parses a sample line: name{label="value",...} value [timestamp].
+func parseMetricLine(line string) (Metric, error) {
+	var m Metric
+
+	end := strings.IndexAny(line, "{ \t")
+	if end <= 0 {
+		return m, fmt.Errorf("invalid sample %q", line)
+	}
+	m.Name, line = line[:end], line[end:]
+
+	if strings.HasPrefix(line, "{") {
+		labels, rest, err := parseMetricLabels(line[1:])
+		if err != nil {
+			return m, err
+		}
+		m.Labels, line = labels, rest
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 || len(fields) > 2 {
+		return m, fmt.Errorf("invalid value of %s: %q", m.Name, line)
+	}
+
+	v, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return m, fmt.Errorf("invalid value of %s: %w", m.Name, err)
+	}
+	m.Value = v
+
+	return m, nil
+}
+
+// parseMetricLabels parses the labels following the opening brace, returning
+// the rest of the line after the closing brace.
+func parseMetricLabels(s string) (map[string]string, string, error) {
+	labels := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+
+		name, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, "", fmt.Errorf("invalid labels %q", s)
+		}
+		name = strings.TrimSpace(name)
+
+		rest = strings.TrimLeft(rest, " \t")
+		if !strings.HasPrefix(rest, `"`) {
+			return nil, "", fmt.Errorf("invalid value of label %q", name)
+		}
+
+		var value strings.Builder
+		i := 1
+		for ; i < len(rest) && rest[i] != '"'; i++ {
+			if rest[i] != '\\' || i+1 == len(rest) {
+				value.WriteByte(rest[i])
+				continue
+			}
+
+			i++
+			switch rest[i] {
+			case 'n':
+				value.WriteByte('\n')
+			default:
+				value.WriteByte(rest[i])
+			}
+		}
+		if i == len(rest) {
+			return nil, "", fmt.Errorf("unterminated value of label %q", name)
+		}
+		labels[name] = value.String()
+
+		s = strings.TrimLeft(rest[i+1:], " \t")
+		s = strings.TrimPrefix(s, ",")
+	}
+}
+
+// scrapeMetricsTimeout is the timeout of the request scraping the metrics with [ScrapeMetrics].
+const scrapeMetricsTimeout = 10 * time.Second
+
+// ScrapeMetrics scrapes the metrics in the Prometheus text exposition format
+// served by the target at path on the host port mapped to port.
+// The request times out after 10 seconds, if ctx isn't done before.
+func ScrapeMetrics(ctx context.Context, target StrategyTarget, port string, path string) (Metrics, error) {
+	host, err := target.Host(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("host: %w", err)
+	}
+
+	mappedPort, err := target.MappedPort(ctx, port)
+	if err != nil {
+		return nil, fmt.Errorf("mapped port: %w", err)
+	}
+
+	return scrapeMetrics(ctx, &http.Client{Timeout: scrapeMetricsTimeout}, host, mappedPort, path)
+}
+
+// scrapeMetrics scrapes the metrics served at path on host:port.
+func scrapeMetrics(ctx context.Context, client *http.Client, host string, port network.Port, path string) (Metrics, error) {
+	endpoint, err := url.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("parse path: %w", err)
+	}
+	endpoint.Scheme = "http"
+	endpoint.Host = net.JoinHostPort(host, port.Port())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain;version=0.0.4")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	return ParseMetrics(resp.Body)
+}
+
+// MetricStrategy waits until a metric scraped from the container, in the
+// Prometheus text exposition format, matches a condition.
+type MetricStrategy struct {
+	// all Strategies should have a startupTimeout to avoid waiting infinitely
+	timeout *time.Duration
+
+	// additional properties
+	Port         network.Port
+	Path         string
+	Name         string
+	Labels       map[string]string
+	Matcher      func(value float64) bool
+	PollInterval time.Duration
+}
+
+// NewMetricStrategy constructs a metric strategy scraping path on port,
+// waiting for the metrics endpoint to be available.
+func NewMetricStrategy(port string, path string) *MetricStrategy {
+	ws := &MetricStrategy{
+		Path:         path,
+		PollInterval: defaultPollInterval(),
+	}
+	if p, err := network.ParsePort(port); err == nil {
+		ws.Port = p
+	}
+
+	return ws
+}
+
+// ForMetric is the default construction for the fluid interface.
+//
+// For Example:
+//
+//	wait.
+//		ForMetric("9090/tcp", "/metrics").
+//		WithName("kafka_server_replicamanager_underreplicatedpartitions").
+//		WithMatcher(func(v float64) bool { return v == 0 })
+func ForMetric(port string, path string) *MetricStrategy {
+	return NewMetricStrategy(port, path)
+}
+
+// fluent builders for each property
+// since go has neither covariance nor generics, the return type must be the type of the concrete implementation
+// this is true for all properties, even the "shared" ones like startupTimeout
+
+// WithStartupTimeout can be used to change the default startup timeout
+func (ws *MetricStrategy) WithStartupTimeout(timeout time.Duration) *MetricStrategy {
+	ws.timeout = &timeout
+	return ws
+}
+
+// WithName sets the name of the metric to check. The strategy waits until a
+// sample of the metric is exposed, matching the labels and matcher if set.
+func (ws *MetricStrategy) WithName(name string) *MetricStrategy {
+	ws.Name = name
+	return ws
+}
+
+// WithLabels restricts the samples checked to the ones having all the labels.
+func (ws *MetricStrategy) WithLabels(labels map[string]string) *MetricStrategy {
+	ws.Labels = labels
+	return ws
+}
+
+// WithMatcher sets the condition the value of the samples must match.
+// All the samples with the name and labels must match it.
+func (ws *MetricStrategy) WithMatcher(matcher func(value float64) bool) *MetricStrategy {
+	ws.Matcher = matcher
+	return ws
+}
+
+// WithPollInterval can be used to override the default polling interval of 100 milliseconds
+func (ws *MetricStrategy) WithPollInterval(pollInterval time.Duration) *MetricStrategy {
+	ws.PollInterval = pollInterval
+	return ws
+}
+
+func (ws *MetricStrategy) Timeout() *time.Duration {
+	return ws.timeout
+}
+
+// String returns a human-readable description of the wait strategy.
+func (ws *MetricStrategy) String() string {
+	metric := "metrics"
+	if ws.Name != "" {
+		metric = fmt.Sprintf("metric %s", Metric{Name: ws.Name, Labels: ws.Labels}.selector())
+	}
+
+	return fmt.Sprintf("%s on port %s path %q", metric, ws.Port.Port(), ws.Path)
+}
+
+// WaitUntilReady implements Strategy.WaitUntilReady
+func (ws *MetricStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
+	timeout := defaultStartupTimeout()
+	if ws.timeout != nil {
+		timeout = *ws.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if ws.Port.IsZero() {
+		return errors.New("no port to scrape the metrics from")
+	}
+
+	host, err := target.Host(ctx)
+	if err != nil {
+		return err
+	}
+
+	mappedPort, err := target.MappedPort(ctx, ws.Port.String())
+	for mappedPort.IsZero() {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-time.After(ws.PollInterval):
+			if err := checkTarget(ctx, target); err != nil {
+				return err
+			}
+
+			mappedPort, err = target.MappedPort(ctx, ws.Port.String())
+		}
+	}
+
+	if mappedPort.Proto() != "tcp" {
+		return errors.New("cannot use HTTP client on non-TCP ports")
+	}
+
+	client := &http.Client{Timeout: time.Second}
+
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Join(lastErr, ctx.Err())
+		case <-time.After(ws.PollInterval):
+			if err := checkTarget(ctx, target); err != nil {
+				return err
+			}
+
+			metrics, err := scrapeMetrics(ctx, client, host, mappedPort, ws.Path)
+			if err != nil {
+				lastErr = err
+				recordAttempt(ctx, ws, Attempt{Err: err, Metric: &MetricAttempt{}})
+				continue
+			}
+
+			found, err := ws.check(metrics)
+			recordAttempt(ctx, ws, Attempt{Err: err, Metric: newMetricAttempt(found)})
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			return nil
+		}
+	}
+}
+
+// check returns the samples of the metric of the strategy, all the samples if
+// no metric name is set, and an error if they don't match the conditions of the strategy.
+func (ws *MetricStrategy) check(metrics Metrics) (Metrics, error) {
+	if ws.Name == "" {
+		return metrics, nil
+	}
+
+	found := metrics.Find(ws.Name, ws.Labels)
+	if len(found) == 0 {
+		return nil, fmt.Errorf("metric %s not found", Metric{Name: ws.Name, Labels: ws.Labels}.selector())
+	}
+
+	if ws.Matcher == nil {
+		return found, nil
+	}
+
+	for _, m := range found {
+		if !ws.Matcher(m.Value) {
+			return found, fmt.Errorf("metric %s not matched", m)
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,144 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMetrics = `# HELP kafka_under_replicated_partitions Under-replicated partitions.
+# TYPE kafka_under_replicated_partitions gauge
+kafka_under_replicated_partitions{topic="orders"} %d
+kafka_under_replicated_partitions{topic="payments",broker="1"} 0
+cache_warm 1 1712345678000
+http_requests_total{method="GET",path="/a\"b\\c\nd"} 1027
+up +Inf
+`
+
+func TestParseMetrics(t *testing.T) {
+	metrics, err := ParseMetrics(strings.NewReader(fmt.Sprintf(testMetrics, 3)))
+	require.NoError(t, err)
+	require.Len(t, metrics, 5)
+
+	require.Equal(t, Metric{
+		Name:   "kafka_under_replicated_partitions",
+		Labels: map[string]string{"topic": "payments", "broker": "1"},
+		Value:  0,
+	}, metrics[1])
+	require.Equal(t, Metric{Name: "cache_warm", Value: 1}, metrics[2])
+	require.Equal(t, "/a\"b\\c\nd", metrics[3].Labels["path"])
+
+	require.Len(t, metrics.Find("kafka_under_replicated_partitions", nil), 2)
+	require.Len(t, metrics.Find("kafka_under_replicated_partitions", map[string]string{"broker": "1"}), 1)
+	require.Empty(t, metrics.Find("kafka_under_replicated_partitions", map[string]string{"topic": "other"}))
+
+	v, ok := metrics.Value("kafka_under_replicated_partitions", map[string]string{"topic": "orders"})
+	require.True(t, ok)
+	require.Equal(t, 3.0, v)
+
+	_, ok = metrics.Value("missing", nil)
+	require.False(t, ok)
+
+	require.Equal(t, `kafka_under_replicated_partitions{broker="1",topic="payments"} 0`, metrics[1].String())
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, line := range []string{
+			"no_value",
+			"bad_value abc",
+			`unterminated{label="value} 1`,
+			`no_quotes{label=value} 1`,
+			"too many fields 1",
+		} {
+			_, err := ParseMetrics(strings.NewReader(line))
+			require.Error(t, err, line)
+		}
+	})
+}
+
+func TestMetricStrategy(t *testing.T) {
+	// The under-replicated partitions decrease with each scrape.
+	var scrapes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, testMetrics, max(0, 3-scrapes.Add(1)))
+	}))
+	t.Cleanup(srv.Close)
+
+	target := addrTarget(t, srv.Listener.Addr().String())
+
+	t.Run("endpoint", func(t *testing.T) {
+		err := ForMetric("9090/tcp", "/metrics").
+			WithStartupTimeout(5*time.Second).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.NoError(t, err)
+	})
+
+	t.Run("matcher", func(t *testing.T) {
+		scrapes.Store(0)
+
+		err := ForMetric("9090/tcp", "/metrics").
+			WithName("kafka_under_replicated_partitions").
+			WithMatcher(func(v float64) bool { return v == 0 }).
+			WithStartupTimeout(5*time.Second).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, scrapes.Load(), int32(3))
+	})
+
+	t.Run("labels", func(t *testing.T) {
+		err := ForMetric("9090/tcp", "/metrics").
+			WithName("kafka_under_replicated_partitions").
+			WithLabels(map[string]string{"topic": "orders"}).
+			WithMatcher(func(v float64) bool { return v > 10 }).
+			WithStartupTimeout(500*time.Millisecond).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, `metric kafka_under_replicated_partitions{topic="orders"} 0 not matched`)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		err := ForMetric("9090/tcp", "/metrics").
+			WithName("missing").
+			WithStartupTimeout(500*time.Millisecond).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "metric missing not found")
+	})
+
+	t.Run("wrong-path", func(t *testing.T) {
+		err := ForMetric("9090/tcp", "/other").
+			WithStartupTimeout(500*time.Millisecond).
+			WithPollInterval(50*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.ErrorContains(t, err, "unexpected HTTP status 404")
+	})
+
+	t.Run("scrape", func(t *testing.T) {
+		metrics, err := ScrapeMetrics(context.Background(), target, "9090/tcp", "/metrics")
+		require.NoError(t, err)
+
+		v, ok := metrics.Value("cache_warm", nil)
+		require.True(t, ok)
+		require.Equal(t, 1.0, v)
+	})
+}
+
+func TestMetricStrategyString(t *testing.T) {
+	require.Equal(t, `metrics on port 9090 path "/metrics"`, ForMetric("9090/tcp", "/metrics").String())
+	require.Equal(t, `metric up{job="app"} on port 9090 path "/metrics"`,
+		ForMetric("9090", "/metrics").WithName("up").WithLabels(map[string]string{"job": "app"}).String())
+}
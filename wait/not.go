@@ -0,0 +1,79 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Implement interface
+var (
+	_ Strategy        = (*NotStrategy)(nil)
+	_ StrategyTimeout = (*NotStrategy)(nil)
+)
+
+// NotStrategy inverts a strategy: it succeeds if the strategy fails.
+type NotStrategy struct {
+	// timeout is the window during which the strategy must not succeed.
+	timeout *time.Duration
+
+	// additional properties
+	Strategy Strategy
+}
+
+// ForNot returns a strategy that succeeds if the supplied strategy fails,
+// typically because its timeout is reached, e.g. a log never being written,
+// and fails if the supplied strategy succeeds.
+//
+// It fails if the container is not running anymore when the supplied strategy
+// fails, or if the parent context is done.
+func ForNot(strategy Strategy) *NotStrategy {
+	return &NotStrategy{
+		Strategy: strategy,
+	}
+}
+
+// WithStartupTimeout sets the window during which the strategy must not succeed.
+// By default, it's the timeout of the strategy.
+func (ws *NotStrategy) WithStartupTimeout(timeout time.Duration) *NotStrategy {
+	ws.timeout = &timeout
+	return ws
+}
+
+func (ws *NotStrategy) Timeout() *time.Duration {
+	return ws.timeout
+}
+
+// String returns a human-readable description of the wait strategy.
+func (ws *NotStrategy) String() string {
+	strategy := "(none)"
+	if !isNilStrategy(ws.Strategy) {
+		strategy = describeStrategy(ws.Strategy)
+	}
+
+	return "not: " + strategy
+}
+
+// WaitUntilReady implements Strategy.WaitUntilReady
+func (ws *NotStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
+	if isNilStrategy(ws.Strategy) {
+		return nil
+	}
+
+	strategyCtx := ctx
+	if ws.timeout != nil {
+		var cancel context.CancelFunc
+		strategyCtx, cancel = context.WithTimeout(ctx, *ws.timeout)
+		defer cancel()
+	}
+
+	if err := ws.Strategy.WaitUntilReady(strategyCtx, target); err == nil {
+		return fmt.Errorf("%s: unexpectedly succeeded", describeStrategy(ws.Strategy))
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", ws, err)
+	}
+
+	return checkTarget(ctx, target)
+}
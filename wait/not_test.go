@@ -0,0 +1,52 @@
+package wait_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestNotStrategy(t *testing.T) {
+	t.Run("strategy-fails", func(t *testing.T) {
+		err := wait.ForNot(newFuncStrategy("stuck", waitForDeadline)).
+			WithStartupTimeout(50*time.Millisecond).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.NoError(t, err)
+	})
+
+	t.Run("strategy-succeeds", func(t *testing.T) {
+		err := wait.ForNot(newFuncStrategy("ok", succeed)).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.EqualError(t, err, "ok: unexpectedly succeeded")
+	})
+
+	t.Run("parent-done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := wait.ForNot(newFuncStrategy("stuck", waitForDeadline)).
+			WaitUntilReady(ctx, runningTarget())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("exited", func(t *testing.T) {
+		target := runningTarget()
+		target.StateImpl = func(_ context.Context) (*container.State, error) {
+			return &container.State{Status: container.StateExited, ExitCode: 1}, nil
+		}
+
+		err := wait.ForNot(newFuncStrategy("failing", func(context.Context, int) error { return errors.New("boom") })).
+			WaitUntilReady(context.Background(), target)
+		require.EqualError(t, err, "container exited with code 1")
+	})
+
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, `not: log message "panic"`, wait.ForNot(wait.ForLog("panic")).String())
+	})
+}
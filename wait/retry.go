@@ -0,0 +1,105 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Implement interface
+var (
+	_ Strategy        = (*RetryStrategy)(nil)
+	_ StrategyTimeout = (*RetryStrategy)(nil)
+)
+
+// RetryStrategy runs a strategy again when it fails, up to a number of attempts.
+type RetryStrategy struct {
+	// timeout limits all the attempts, if set.
+	timeout *time.Duration
+
+	// additional properties
+	Strategy     Strategy
+	Attempts     int
+	PollInterval time.Duration
+}
+
+// ForRetry returns a strategy that runs the supplied strategy until it succeeds,
+// up to the given number of attempts. A [PermanentError] stops the attempts.
+func ForRetry(strategy Strategy, attempts int) *RetryStrategy {
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	return &RetryStrategy{
+		Strategy:     strategy,
+		Attempts:     attempts,
+		PollInterval: defaultPollInterval(),
+	}
+}
+
+// WithStartupTimeout sets a timeout which limits all the attempts.
+// By default, the attempts are only limited by the timeout of the strategy.
+func (ws *RetryStrategy) WithStartupTimeout(timeout time.Duration) *RetryStrategy {
+	ws.timeout = &timeout
+	return ws
+}
+
+// WithPollInterval can be used to override the default delay of 100 milliseconds between the attempts
+func (ws *RetryStrategy) WithPollInterval(pollInterval time.Duration) *RetryStrategy {
+	ws.PollInterval = pollInterval
+	return ws
+}
+
+func (ws *RetryStrategy) Timeout() *time.Duration {
+	return ws.timeout
+}
+
+// String returns a human-readable description of the wait strategy.
+func (ws *RetryStrategy) String() string {
+	strategy := "(none)"
+	if !isNilStrategy(ws.Strategy) {
+		strategy = describeStrategy(ws.Strategy)
+	}
+
+	return fmt.Sprintf("retry %d attempts: %s", ws.Attempts, strategy)
+}
+
+// WaitUntilReady implements Strategy.WaitUntilReady
+func (ws *RetryStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
+	if isNilStrategy(ws.Strategy) {
+		return nil
+	}
+
+	if ws.timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *ws.timeout)
+		defer cancel()
+	}
+
+	var err error
+	for attempt := 1; attempt <= ws.Attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%s: attempt %d of %d: %w", describeStrategy(ws.Strategy), attempt-1, ws.Attempts, errors.Join(err, ctx.Err()))
+			case <-time.After(ws.PollInterval):
+			}
+
+			if errTarget := checkTarget(ctx, target); errTarget != nil {
+				return errTarget
+			}
+		}
+
+		if err = ws.Strategy.WaitUntilReady(ctx, target); err == nil {
+			return nil
+		}
+
+		var errPermanent *PermanentError
+		if errors.As(err, &errPermanent) {
+			return fmt.Errorf("%s: attempt %d of %d: %w", describeStrategy(ws.Strategy), attempt, ws.Attempts, err)
+		}
+	}
+
+	return fmt.Errorf("%s: all %d attempts failed, last: %w", describeStrategy(ws.Strategy), ws.Attempts, err)
+}
@@ -0,0 +1,69 @@
+package wait_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestRetryStrategy(t *testing.T) {
+	t.Run("succeeds", func(t *testing.T) {
+		eventually := newFuncStrategy("eventually", func(_ context.Context, call int) error {
+			if call < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		})
+
+		err := wait.ForRetry(eventually, 3).
+			WithPollInterval(10*time.Millisecond).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.NoError(t, err)
+		require.Equal(t, int32(3), eventually.calls.Load())
+	})
+
+	t.Run("all-attempts-fail", func(t *testing.T) {
+		failing := newFuncStrategy("failing", func(context.Context, int) error {
+			return errors.New("boom")
+		})
+
+		err := wait.ForRetry(failing, 2).
+			WithPollInterval(10*time.Millisecond).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.EqualError(t, err, "failing: all 2 attempts failed, last: boom")
+		require.Equal(t, int32(2), failing.calls.Load())
+	})
+
+	t.Run("permanent-error", func(t *testing.T) {
+		failing := newFuncStrategy("failing", func(context.Context, int) error {
+			return wait.NewPermanentError(errors.New("fatal"))
+		})
+
+		err := wait.ForRetry(failing, 5).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.EqualError(t, err, "failing: attempt 1 of 5: fatal")
+		require.Equal(t, int32(1), failing.calls.Load())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		failing := newFuncStrategy("failing", func(context.Context, int) error {
+			return errors.New("boom")
+		})
+
+		err := wait.ForRetry(failing, 1000).
+			WithStartupTimeout(100*time.Millisecond).
+			WithPollInterval(10*time.Millisecond).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "boom")
+	})
+
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, "retry 3 attempts: ok", wait.ForRetry(newFuncStrategy("ok", succeed), 3).String())
+	})
+}
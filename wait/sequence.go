@@ -0,0 +1,113 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Implement interface
+var (
+	_ Strategy        = (*SequenceStrategy)(nil)
+	_ StrategyTimeout = (*SequenceStrategy)(nil)
+)
+
+// SequenceStrategy waits for its strategies strictly in order, each one with
+// its own deadline, starting when the previous one succeeded.
+type SequenceStrategy struct {
+	// all Strategies should have a startupTimeout to avoid waiting infinitely
+	timeout  *time.Duration
+	deadline *time.Duration
+
+	// additional properties
+	Strategies []Strategy
+}
+
+// ForSequence returns a strategy that waits for the supplied strategies in
+// order, each one being given its own timeout when it starts: the one set on
+// the strategy if any, or the default set with
+// [SequenceStrategy.WithStartupTimeoutDefault].
+//
+// The first failure is returned, identifying the failed step.
+func ForSequence(strategies ...Strategy) *SequenceStrategy {
+	return &SequenceStrategy{
+		Strategies: strategies,
+	}
+}
+
+// WithStartupTimeoutDefault sets the default timeout of each step of the sequence.
+func (ss *SequenceStrategy) WithStartupTimeoutDefault(timeout time.Duration) *SequenceStrategy {
+	ss.timeout = &timeout
+	return ss
+}
+
+// WithDeadline sets a time.Duration which limits the whole sequence.
+func (ss *SequenceStrategy) WithDeadline(deadline time.Duration) *SequenceStrategy {
+	ss.deadline = &deadline
+	return ss
+}
+
+func (ss *SequenceStrategy) Timeout() *time.Duration {
+	return ss.timeout
+}
+
+// String returns a human-readable description of the wait strategy.
+func (ss *SequenceStrategy) String() string {
+	var strategies []string
+	for _, strategy := range ss.Strategies {
+		if isNilStrategy(strategy) {
+			continue
+		}
+		strategies = append(strategies, describeStrategy(strategy))
+	}
+
+	if len(strategies) == 0 {
+		return "sequence of: (none)"
+	}
+
+	return "sequence of: [" + strings.Join(strategies, ", ") + "]"
+}
+
+// WaitUntilReady implements Strategy.WaitUntilReady
+func (ss *SequenceStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
+	if ss.deadline != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *ss.deadline)
+		defer cancel()
+	}
+
+	if len(ss.Strategies) == 0 {
+		return errors.New("no wait strategy supplied")
+	}
+
+	for i, strategy := range ss.Strategies {
+		if isNilStrategy(strategy) {
+			// Skip the strategies not initialized, as ForAll does.
+			continue
+		}
+
+		if err := ss.waitStep(ctx, strategy, target); err != nil {
+			return fmt.Errorf("sequence step %d (%s): %w", i+1, describeStrategy(strategy), err)
+		}
+	}
+
+	return nil
+}
+
+// waitStep waits for the strategy, with its own timeout.
+func (ss *SequenceStrategy) waitStep(ctx context.Context, strategy Strategy, target StrategyTarget) error {
+	timeout := ss.timeout
+	if st, ok := strategy.(StrategyTimeout); ok && st.Timeout() != nil {
+		timeout = st.Timeout()
+	}
+
+	if timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	return strategy.WaitUntilReady(ctx, target)
+}
@@ -0,0 +1,129 @@
+package wait_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// funcStrategy is a strategy calling a function, counting its calls.
+type funcStrategy struct {
+	name    string
+	timeout *time.Duration
+	calls   atomic.Int32
+	fn      func(ctx context.Context, call int) error
+}
+
+func newFuncStrategy(name string, fn func(ctx context.Context, call int) error) *funcStrategy {
+	return &funcStrategy{name: name, fn: fn}
+}
+
+func (s *funcStrategy) WaitUntilReady(ctx context.Context, _ wait.StrategyTarget) error {
+	return s.fn(ctx, int(s.calls.Add(1)))
+}
+
+func (s *funcStrategy) Timeout() *time.Duration {
+	return s.timeout
+}
+
+func (s *funcStrategy) String() string {
+	return s.name
+}
+
+// runningTarget returns a target whose container is running.
+func runningTarget() *wait.MockStrategyTarget {
+	return &wait.MockStrategyTarget{
+		StateImpl: func(_ context.Context) (*container.State, error) {
+			return &container.State{Running: true}, nil
+		},
+	}
+}
+
+// succeed is a strategy function that succeeds immediately.
+func succeed(context.Context, int) error {
+	return nil
+}
+
+// waitForDeadline is a strategy function that never succeeds, returning when ctx is done.
+func waitForDeadline(ctx context.Context, _ int) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestSequenceStrategy(t *testing.T) {
+	t.Run("in-order", func(t *testing.T) {
+		var order []string
+		step := func(name string) wait.Strategy {
+			return newFuncStrategy(name, func(context.Context, int) error {
+				order = append(order, name)
+				return nil
+			})
+		}
+
+		err := wait.ForSequence(step("first"), nil, step("second"), step("third")).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.NoError(t, err)
+		require.Equal(t, []string{"first", "second", "third"}, order)
+	})
+
+	t.Run("step-deadline", func(t *testing.T) {
+		// Each step gets its own timeout, starting when the previous one succeeded.
+		slow := newFuncStrategy("slow", func(ctx context.Context, _ int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(300 * time.Millisecond):
+				return nil
+			}
+		})
+		stuck := newFuncStrategy("stuck", waitForDeadline)
+
+		start := time.Now()
+		err := wait.ForSequence(slow, stuck).
+			WithStartupTimeoutDefault(500*time.Millisecond).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.EqualError(t, err, "sequence step 2 (stuck): context deadline exceeded")
+		require.GreaterOrEqual(t, time.Since(start), 800*time.Millisecond)
+	})
+
+	t.Run("strategy-timeout", func(t *testing.T) {
+		timeout := 100 * time.Millisecond
+		stuck := newFuncStrategy("stuck", waitForDeadline)
+		stuck.timeout = &timeout
+
+		err := wait.ForSequence(stuck).
+			WithStartupTimeoutDefault(time.Minute).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("failure-stops", func(t *testing.T) {
+		last := newFuncStrategy("last", succeed)
+		err := wait.ForSequence(
+			newFuncStrategy("first", succeed),
+			newFuncStrategy("failing", func(context.Context, int) error { return errors.New("boom") }),
+			last,
+		).WaitUntilReady(context.Background(), runningTarget())
+		require.EqualError(t, err, "sequence step 2 (failing): boom")
+		require.Zero(t, last.calls.Load())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		err := wait.ForSequence().WaitUntilReady(context.Background(), runningTarget())
+		require.EqualError(t, err, "no wait strategy supplied")
+	})
+
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, "sequence of: [log message \"ready\", first]",
+			wait.ForSequence(wait.ForLog("ready"), nil, newFuncStrategy("first", succeed)).String())
+		require.Equal(t, "sequence of: (none)", wait.ForSequence().String())
+	})
+}
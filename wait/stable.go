@@ -0,0 +1,126 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Implement interface
+var (
+	_ Strategy        = (*StableStrategy)(nil)
+	_ StrategyTimeout = (*StableStrategy)(nil)
+)
+
+// StableStrategy waits until a strategy succeeds a number of consecutive times,
+// for services whose readiness flaps while they start.
+type StableStrategy struct {
+	// all Strategies should have a startupTimeout to avoid waiting infinitely
+	timeout *time.Duration
+
+	// additional properties
+	Strategy     Strategy
+	Consecutive  int
+	Interval     time.Duration
+	CheckTimeout time.Duration
+}
+
+// ForStable returns a strategy that checks the supplied strategy every interval,
+// until it succeeds consecutive times in a row. Each check must succeed within
+// the interval, unless a different check timeout is set with
+// [StableStrategy.WithCheckTimeout], otherwise the count starts over.
+func ForStable(strategy Strategy, consecutive int, interval time.Duration) *StableStrategy {
+	if consecutive <= 0 {
+		consecutive = 1
+	}
+
+	return &StableStrategy{
+		Strategy:     strategy,
+		Consecutive:  consecutive,
+		Interval:     interval,
+		CheckTimeout: interval,
+	}
+}
+
+// WithStartupTimeout can be used to change the default startup timeout
+func (ws *StableStrategy) WithStartupTimeout(timeout time.Duration) *StableStrategy {
+	ws.timeout = &timeout
+	return ws
+}
+
+// WithCheckTimeout sets the maximum time each check of the strategy can take to succeed.
+// Default is the interval between the checks.
+func (ws *StableStrategy) WithCheckTimeout(timeout time.Duration) *StableStrategy {
+	ws.CheckTimeout = timeout
+	return ws
+}
+
+func (ws *StableStrategy) Timeout() *time.Duration {
+	return ws.timeout
+}
+
+// String returns a human-readable description of the wait strategy.
+func (ws *StableStrategy) String() string {
+	strategy := "(none)"
+	if !isNilStrategy(ws.Strategy) {
+		strategy = describeStrategy(ws.Strategy)
+	}
+
+	return fmt.Sprintf("stable %d times every %s: %s", ws.Consecutive, ws.Interval, strategy)
+}
+
+// WaitUntilReady implements Strategy.WaitUntilReady
+func (ws *StableStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
+	if isNilStrategy(ws.Strategy) {
+		return nil
+	}
+
+	timeout := defaultStartupTimeout()
+	if ws.timeout != nil {
+		timeout = *ws.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var successes int
+	var lastErr error
+	for {
+		if err := checkTarget(ctx, target); err != nil {
+			return err
+		}
+
+		if err := ws.check(ctx, target); err != nil {
+			successes = 0
+			lastErr = err
+		} else {
+			successes++
+		}
+
+		if successes >= ws.Consecutive {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			err := fmt.Errorf("%s: succeeded %d of %d consecutive times", describeStrategy(ws.Strategy), successes, ws.Consecutive)
+			if lastErr != nil {
+				err = fmt.Errorf("%w, last failure: %w", err, lastErr)
+			}
+			return errors.Join(err, ctx.Err())
+		case <-time.After(ws.Interval):
+		}
+	}
+}
+
+// check runs the strategy once, limited by the check timeout.
+func (ws *StableStrategy) check(ctx context.Context, target StrategyTarget) error {
+	if ws.CheckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ws.CheckTimeout)
+		defer cancel()
+	}
+
+	return ws.Strategy.WaitUntilReady(ctx, target)
+}
@@ -0,0 +1,73 @@
+package wait_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestStableStrategy(t *testing.T) {
+	t.Run("consecutive", func(t *testing.T) {
+		// Flaps on the third call, then is stable.
+		flapping := newFuncStrategy("flapping", func(_ context.Context, call int) error {
+			if call == 3 {
+				return errors.New("not ready")
+			}
+			return nil
+		})
+
+		err := wait.ForStable(flapping, 3, 10*time.Millisecond).
+			WithStartupTimeout(5*time.Second).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.NoError(t, err)
+		require.Equal(t, int32(6), flapping.calls.Load())
+	})
+
+	t.Run("never-stable", func(t *testing.T) {
+		flapping := newFuncStrategy("flapping", func(_ context.Context, call int) error {
+			if call%2 == 0 {
+				return errors.New("not ready")
+			}
+			return nil
+		})
+
+		err := wait.ForStable(flapping, 2, 10*time.Millisecond).
+			WithStartupTimeout(200*time.Millisecond).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "flapping: succeeded")
+		require.ErrorContains(t, err, "of 2 consecutive times, last failure: not ready")
+	})
+
+	t.Run("check-timeout", func(t *testing.T) {
+		stuck := newFuncStrategy("stuck", waitForDeadline)
+
+		err := wait.ForStable(stuck, 1, 10*time.Millisecond).
+			WithCheckTimeout(20*time.Millisecond).
+			WithStartupTimeout(200*time.Millisecond).
+			WaitUntilReady(context.Background(), runningTarget())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Greater(t, stuck.calls.Load(), int32(1))
+	})
+
+	t.Run("exited", func(t *testing.T) {
+		target := runningTarget()
+		target.StateImpl = func(_ context.Context) (*container.State, error) {
+			return &container.State{Status: container.StateExited, ExitCode: 2}, nil
+		}
+
+		err := wait.ForStable(newFuncStrategy("ok", succeed), 2, 10*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.EqualError(t, err, "container exited with code 2")
+	})
+
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, "stable 3 times every 1s: ok", wait.ForStable(newFuncStrategy("ok", succeed), 3, time.Second).String())
+	})
+}
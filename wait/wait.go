@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
 	"time"
 
 	"github.com/moby/moby/api/types/container"
@@ -63,3 +64,19 @@ func defaultStartupTimeout() time.Duration {
 func defaultPollInterval() time.Duration {
 	return 100 * time.Millisecond
 }
+
+// isNilStrategy reports whether the strategy is nil, including typed nil pointers,
+// which can be found when a module uses a strategy before it's initialized.
+func isNilStrategy(strategy Strategy) bool {
+	return strategy == nil || reflect.ValueOf(strategy).IsNil()
+}
+
+// describeStrategy returns a human-readable description of the strategy,
+// its type if it doesn't implement [fmt.Stringer].
+func describeStrategy(strategy Strategy) string {
+	if s, ok := strategy.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return fmt.Sprintf("%T", strategy)
+}
@@ -0,0 +1,443 @@
+package wait
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+// maxExcerptSize is the maximum number of bytes of an HTTP body or an exec
+// output kept in an [Attempt].
+const maxExcerptSize = 512
+
+// WaitError is returned by [WaitUntilReady] when the strategy fails, describing
+// the last attempt of each nested strategy and the state of the container.
+//
+// Use [errors.As] to retrieve it, and [WaitError.Summary] to report it.
+type WaitError struct {
+	// Err is the error returned by the strategy.
+	Err error
+
+	// Attempts are the last attempts of the strategies, in the order they started.
+	Attempts []Attempt
+
+	// State is the state of the container when the strategy failed, if it could be retrieved.
+	State *container.State
+}
+
+// Error implements the error interface.
+func (e *WaitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error returned by the strategy.
+func (e *WaitError) Unwrap() error {
+	return e.Err
+}
+
+// Summary returns a multi-line description of the failure, the state of the
+// container and the last attempt of each strategy, suitable for test output.
+func (e *WaitError) Summary() string {
+	var sb strings.Builder
+	sb.WriteString(e.Err.Error())
+
+	if e.State != nil {
+		fmt.Fprintf(&sb, "\ncontainer state: %s", describeState(e.State))
+	}
+
+	if len(e.Attempts) > 0 {
+		sb.WriteString("\nlast attempts:")
+		for _, a := range e.Attempts {
+			sb.WriteString("\n  - ")
+			sb.WriteString(a.String())
+		}
+	}
+
+	return sb.String()
+}
+
+// Format implements fmt.Formatter, printing the summary with the %+v verb.
+func (e *WaitError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		_, _ = io.WriteString(s, e.Summary())
+		return
+	}
+
+	_, _ = io.WriteString(s, e.Error())
+}
+
+// describeState returns a human-readable description of the container state.
+func describeState(state *container.State) string {
+	desc := string(state.Status)
+	if desc == "" {
+		desc = "unknown"
+	}
+
+	switch {
+	case state.OOMKilled:
+		desc += " (OOMKilled)"
+	case state.Status == container.StateExited:
+		desc += fmt.Sprintf(" (exit code %d)", state.ExitCode)
+	}
+
+	if state.Health != nil {
+		desc += fmt.Sprintf(", health: %s", state.Health.Status)
+	}
+
+	if state.Error != "" {
+		desc += fmt.Sprintf(", error: %s", state.Error)
+	}
+
+	return desc
+}
+
+// Attempt describes the last attempt of a strategy.
+// Only the details relevant to the strategy are set.
+type Attempt struct {
+	// Strategy is the description of the strategy.
+	Strategy string
+
+	// Time is when the attempt was made.
+	Time time.Time
+
+	// Err is why the attempt failed, nil if it succeeded.
+	Err error
+
+	// HTTP is the response received by an HTTP strategy.
+	HTTP *HTTPAttempt
+
+	// Exec is the result of the command run by an exec strategy.
+	Exec *ExecAttempt
+
+	// Log is the result of the search of a log strategy.
+	Log *LogAttempt
+
+	// Port is the result of the checks of a host port strategy.
+	Port *PortAttempt
+
+	// Health is the health of the container checked by a health strategy.
+	Health *HealthAttempt
+
+	// GRPC is the response received by a gRPC health strategy.
+	GRPC *GRPCAttempt
+
+	// Metric is the result of the scrape of a metric strategy.
+	Metric *MetricAttempt
+}
+
+// String returns a human-readable description of the attempt.
+func (a Attempt) String() string {
+	var details []string
+	if a.HTTP != nil {
+		details = append(details, a.HTTP.String())
+	}
+	if a.Exec != nil {
+		details = append(details, a.Exec.String())
+	}
+	if a.Log != nil {
+		details = append(details, a.Log.String())
+	}
+	if a.Port != nil {
+		details = append(details, a.Port.String())
+	}
+	if a.Health != nil {
+		details = append(details, a.Health.String())
+	}
+	if a.GRPC != nil {
+		details = append(details, a.GRPC.String())
+	}
+	if a.Metric != nil {
+		details = append(details, a.Metric.String())
+	}
+
+	result := "succeeded"
+	if a.Err != nil {
+		result = "failed: " + a.Err.Error()
+	}
+	details = append(details, result)
+
+	return fmt.Sprintf("%s at %s: %s", a.Strategy, a.Time.Format("15:04:05.000"), strings.Join(details, ", "))
+}
+
+// HTTPAttempt is the response received by an HTTP strategy.
+type HTTPAttempt struct {
+	// StatusCode is the status code of the response, zero if there was no response.
+	StatusCode int
+
+	// Body is an excerpt of the response body.
+	Body string
+}
+
+// String returns a human-readable description of the response.
+func (a HTTPAttempt) String() string {
+	if a.StatusCode == 0 {
+		return "no response"
+	}
+
+	return fmt.Sprintf("status %d, body %q", a.StatusCode, a.Body)
+}
+
+// ExecAttempt is the result of the command run by an exec strategy.
+type ExecAttempt struct {
+	// ExitCode is the exit code of the command.
+	ExitCode int
+
+	// Output is an excerpt of the output of the command.
+	Output string
+}
+
+// String returns a human-readable description of the result.
+func (a ExecAttempt) String() string {
+	return fmt.Sprintf("exit code %d, output %q", a.ExitCode, a.Output)
+}
+
+// LogAttempt is the result of the search of a log strategy.
+type LogAttempt struct {
+	// Matches is the number of times the log was found.
+	Matches int
+
+	// Occurrence is the number of times the log must be found.
+	Occurrence int
+}
+
+// String returns a human-readable description of the result.
+func (a LogAttempt) String() string {
+	return fmt.Sprintf("matched %d of %d times", a.Matches, a.Occurrence)
+}
+
+// PortAttempt is the result of the checks of a host port strategy.
+type PortAttempt struct {
+	// Port is the container port.
+	Port string
+
+	// MappedPort is the host port mapped to the container port, empty if not mapped yet.
+	MappedPort string
+
+	// External is the result of the check from the host, empty if not run.
+	External string
+
+	// Internal is the result of the check inside the container, empty if not run.
+	Internal string
+}
+
+// String returns a human-readable description of the checks.
+func (a PortAttempt) String() string {
+	mapped := a.MappedPort
+	if mapped == "" {
+		mapped = "not mapped"
+	}
+
+	desc := fmt.Sprintf("port %s mapped to %s", a.Port, mapped)
+	if a.External != "" {
+		desc += ", external check: " + a.External
+	}
+	if a.Internal != "" {
+		desc += ", internal check: " + a.Internal
+	}
+
+	return desc
+}
+
+// HealthAttempt is the health of the container checked by a health strategy.
+type HealthAttempt struct {
+	// Status is the health status of the container.
+	Status container.HealthStatus
+
+	// ExitCode is the exit code of the last healthcheck, if any.
+	ExitCode int
+
+	// Output is an excerpt of the output of the last healthcheck, if any.
+	Output string
+}
+
+// newHealthAttempt returns the health attempt of the health of the container,
+// nil if the health of the container is not reported.
+func newHealthAttempt(health *container.Health) *HealthAttempt {
+	if health == nil {
+		return nil
+	}
+
+	attempt := &HealthAttempt{Status: health.Status}
+	if n := len(health.Log); n > 0 && health.Log[n-1] != nil {
+		last := health.Log[n-1]
+		attempt.ExitCode = last.ExitCode
+		attempt.Output = last.Output
+		if len(attempt.Output) > maxExcerptSize {
+			attempt.Output = attempt.Output[:maxExcerptSize]
+		}
+	}
+
+	return attempt
+}
+
+// String returns a human-readable description of the health.
+func (a HealthAttempt) String() string {
+	return fmt.Sprintf("health status %s, exit code %d, output %q", a.Status, a.ExitCode, a.Output)
+}
+
+// GRPCAttempt is the response received by a gRPC health strategy.
+type GRPCAttempt struct {
+	// Status is the serving status of the service, unknown if there was no response.
+	Status GRPCHealthStatus
+}
+
+// String returns a human-readable description of the response.
+func (a GRPCAttempt) String() string {
+	return fmt.Sprintf("health status %s", a.Status)
+}
+
+// maxAttemptSamples is the maximum number of samples kept in a [MetricAttempt].
+const maxAttemptSamples = 5
+
+// MetricAttempt is the result of the scrape of a metric strategy.
+type MetricAttempt struct {
+	// Samples are the first samples of the metric found, empty if the metric was
+	// not found or the metrics could not be scraped.
+	Samples []Metric
+
+	// Found is the number of samples of the metric found.
+	Found int
+}
+
+// newMetricAttempt returns the metric attempt of the samples found.
+func newMetricAttempt(found []Metric) *MetricAttempt {
+	return &MetricAttempt{
+		Samples: slices.Clone(found[:min(len(found), maxAttemptSamples)]),
+		Found:   len(found),
+	}
+}
+
+// String returns a human-readable description of the result.
+func (a MetricAttempt) String() string {
+	if a.Found == 0 {
+		return "no samples"
+	}
+
+	samples := make([]string, len(a.Samples))
+	for i, m := range a.Samples {
+		samples[i] = m.String()
+	}
+
+	desc := fmt.Sprintf("%d samples: %s", a.Found, strings.Join(samples, ", "))
+	if a.Found > len(a.Samples) {
+		desc += ", ..."
+	}
+
+	return desc
+}
+
+// WaitUntilReady waits for the strategy, returning a [*WaitError] if it fails,
+// which records the last attempt of each nested strategy and the state of the
+// container.
+func WaitUntilReady(ctx context.Context, strategy Strategy, target StrategyTarget) error {
+	recorder := &attemptRecorder{index: make(map[Strategy]int)}
+
+	err := strategy.WaitUntilReady(context.WithValue(ctx, attemptRecorderKey{}, recorder), target)
+	if err == nil {
+		return nil
+	}
+
+	waitErr := &WaitError{
+		Err:      err,
+		Attempts: recorder.snapshot(),
+	}
+
+	// The context could be done, so use a new one to get the state.
+	stateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if state, errState := target.State(stateCtx); errState == nil {
+		waitErr.State = state
+	}
+
+	return waitErr
+}
+
+// attemptRecorderKey is the context key of the attempt recorder.
+type attemptRecorderKey struct{}
+
+// attemptRecorder records the last attempt of each strategy.
+// It's safe for concurrent use, as strategies can run in parallel.
+type attemptRecorder struct {
+	mtx      sync.Mutex
+	attempts []Attempt
+	index    map[Strategy]int
+}
+
+// record records the attempt as the last one of the strategy.
+func (r *attemptRecorder) record(strategy Strategy, attempt Attempt) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if i, ok := r.index[strategy]; ok {
+		r.attempts[i] = attempt
+		return
+	}
+
+	r.index[strategy] = len(r.attempts)
+	r.attempts = append(r.attempts, attempt)
+}
+
+// snapshot returns a copy of the recorded attempts.
+func (r *attemptRecorder) snapshot() []Attempt {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return append([]Attempt(nil), r.attempts...)
+}
+
+// recordAttempt records the attempt as the last one of the strategy, if the
+// context was created by [WaitUntilReady].
+func recordAttempt(ctx context.Context, strategy Strategy, attempt Attempt) {
+	recorder, ok := ctx.Value(attemptRecorderKey{}).(*attemptRecorder)
+	if !ok || !reflect.TypeOf(strategy).Comparable() {
+		return
+	}
+
+	attempt.Strategy = describeStrategy(strategy)
+	attempt.Time = time.Now()
+	recorder.record(strategy, attempt)
+}
+
+// errNotMatched is the error of an attempt whose result didn't match.
+var errNotMatched = errors.New("not matched")
+
+// excerptReader is a reader which keeps the first bytes read, so that a body
+// consumed by a matcher can still be reported.
+type excerptReader struct {
+	r       io.Reader
+	excerpt bytes.Buffer
+}
+
+// newExcerptReader returns an excerptReader reading from r.
+func newExcerptReader(r io.Reader) *excerptReader {
+	return &excerptReader{r: r}
+}
+
+// Read implements io.Reader.
+func (er *excerptReader) Read(p []byte) (int, error) {
+	n, err := er.r.Read(p)
+	if remaining := maxExcerptSize - er.excerpt.Len(); remaining > 0 {
+		er.excerpt.Write(p[:min(n, remaining)])
+	}
+
+	return n, err
+}
+
+// String returns the excerpt, reading the rest of it if it wasn't read yet.
+func (er *excerptReader) String() string {
+	if remaining := maxExcerptSize - er.excerpt.Len(); remaining > 0 && er.r != nil {
+		_, _ = io.Copy(&er.excerpt, io.LimitReader(er.r, int64(remaining)))
+	}
+
+	return er.excerpt.String()
+}
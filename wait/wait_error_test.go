@@ -0,0 +1,252 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/require"
+
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+func TestWaitUntilReady(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		target := &MockStrategyTarget{
+			StateImpl: func(_ context.Context) (*container.State, error) {
+				return &container.State{Running: true}, nil
+			},
+			ExecImpl: func(_ context.Context, _ []string, _ ...tcexec.ProcessOption) (int, io.Reader, error) {
+				return 0, strings.NewReader(""), nil
+			},
+		}
+
+		err := WaitUntilReady(context.Background(), ForExec([]string{"true"}).WithPollInterval(10*time.Millisecond), target)
+		require.NoError(t, err)
+	})
+
+	t.Run("last-attempts", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = io.WriteString(w, "starting up")
+		}))
+		t.Cleanup(server.Close)
+
+		target := addrTarget(t, server.Listener.Addr().String())
+		target.ExecImpl = func(_ context.Context, _ []string, _ ...tcexec.ProcessOption) (int, io.Reader, error) {
+			return 1, strings.NewReader("connection refused"), nil
+		}
+		target.LogsImpl = func(_ context.Context) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("ready\n")), nil
+		}
+
+		strategy := ForAny(
+			ForHTTP("/health").WithPort("8080/tcp").WithPollInterval(10*time.Millisecond),
+			ForExec([]string{"pg_isready"}).WithPollInterval(10*time.Millisecond),
+			ForLog("ready").WithOccurrence(2).WithPollInterval(10*time.Millisecond),
+		).WithDeadline(500 * time.Millisecond)
+
+		err := WaitUntilReady(context.Background(), strategy, target)
+		require.Error(t, err)
+
+		var waitErr *WaitError
+		require.ErrorAs(t, fmt.Errorf("wrapped: %w", err), &waitErr)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, err.Error(), waitErr.Err.Error())
+		require.NotNil(t, waitErr.State)
+		require.True(t, waitErr.State.Running)
+		require.Len(t, waitErr.Attempts, 3)
+
+		attempts := make(map[string]Attempt)
+		for _, a := range waitErr.Attempts {
+			require.Error(t, a.Err)
+			attempts[a.Strategy] = a
+		}
+
+		httpAttempt := attempts[`HTTP GET request on port 8080 path "/health"`]
+		require.NotNil(t, httpAttempt.HTTP, attempts)
+		require.Equal(t, HTTPAttempt{StatusCode: http.StatusServiceUnavailable, Body: "starting up"}, *httpAttempt.HTTP)
+
+		execAttempt := attempts[`exec command "pg_isready"`]
+		require.NotNil(t, execAttempt.Exec, attempts)
+		require.Equal(t, ExecAttempt{ExitCode: 1, Output: "connection refused"}, *execAttempt.Exec)
+
+		logAttempt := attempts[`log message "ready" (occurrence: 2)`]
+		require.NotNil(t, logAttempt.Log, attempts)
+		require.Equal(t, LogAttempt{Matches: 1, Occurrence: 2}, *logAttempt.Log)
+
+		summary := waitErr.Summary()
+		require.True(t, strings.HasPrefix(summary, err.Error()+"\ncontainer state: "))
+		require.Contains(t, summary, `status 503, body "starting up", failed: status code 503: not matched`)
+		require.Contains(t, summary, `exit code 1, output "connection refused", failed: exit code: not matched`)
+		require.Contains(t, summary, `matched 1 of 2 times, failed: "ready" matched 1 times, expected 2`)
+		require.Equal(t, summary, fmt.Sprintf("%+v", waitErr))
+		require.Equal(t, err.Error(), fmt.Sprintf("%v", waitErr))
+	})
+
+	t.Run("sequence", func(t *testing.T) {
+		exitCode := 0
+		target := &MockStrategyTarget{
+			StateImpl: func(_ context.Context) (*container.State, error) {
+				return &container.State{Running: true, Status: container.StateRunning}, nil
+			},
+			ExecImpl: func(_ context.Context, _ []string, _ ...tcexec.ProcessOption) (int, io.Reader, error) {
+				return exitCode, strings.NewReader(""), nil
+			},
+		}
+
+		strategy := ForAll(
+			ForExec([]string{"first"}).WithPollInterval(10*time.Millisecond),
+			ForExec([]string{"second"}).WithPollInterval(10*time.Millisecond).WithExitCodeMatcher(func(code int) bool {
+				return code == 1
+			}).WithStartupTimeout(200*time.Millisecond),
+		)
+
+		err := WaitUntilReady(context.Background(), strategy, target)
+
+		var waitErr *WaitError
+		require.ErrorAs(t, err, &waitErr)
+		require.Len(t, waitErr.Attempts, 2)
+		require.Equal(t, `exec command "first"`, waitErr.Attempts[0].Strategy)
+		require.NoError(t, waitErr.Attempts[0].Err)
+		require.Equal(t, `exec command "second"`, waitErr.Attempts[1].Strategy)
+		require.Error(t, waitErr.Attempts[1].Err)
+		require.Contains(t, waitErr.Summary(), "\ncontainer state: running\n")
+		require.Contains(t, waitErr.Summary(), `exit code 0, output "", succeeded`)
+	})
+
+	t.Run("port", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		target := addrTarget(t, addr)
+		strategy := ForListeningPort("8080/tcp").
+			SkipInternalCheck().
+			WithPollInterval(10 * time.Millisecond).
+			WithStartupTimeout(200 * time.Millisecond)
+
+		err = WaitUntilReady(context.Background(), strategy, target)
+
+		var waitErr *WaitError
+		require.ErrorAs(t, err, &waitErr)
+		require.Len(t, waitErr.Attempts, 1)
+
+		port := waitErr.Attempts[0].Port
+		require.NotNil(t, port)
+		require.Equal(t, "8080/tcp", port.Port)
+		_, mapped, _ := net.SplitHostPort(addr)
+		require.Equal(t, mapped+"/tcp", port.MappedPort)
+		require.Contains(t, port.External, "refused")
+		require.Empty(t, port.Internal)
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		addr := startH2CServer(t, grpcHealthHandler(1000))
+		strategy := ForGRPCHealth("50051/tcp").
+			WithPollInterval(10 * time.Millisecond).
+			WithStartupTimeout(200 * time.Millisecond)
+
+		err := WaitUntilReady(context.Background(), strategy, addrTarget(t, addr))
+
+		var waitErr *WaitError
+		require.ErrorAs(t, err, &waitErr)
+		require.Len(t, waitErr.Attempts, 1)
+		require.ErrorIs(t, waitErr.Attempts[0].Err, errNotMatched)
+		require.Equal(t, &GRPCAttempt{Status: GRPCHealthStatusNotServing}, waitErr.Attempts[0].GRPC)
+		require.Contains(t, waitErr.Summary(), "health status NOT_SERVING, failed: health status: not matched")
+	})
+
+	t.Run("metric", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, "queue_size{queue=\"orders\"} 12\nqueue_size{queue=\"payments\"} 3\n")
+		}))
+		t.Cleanup(server.Close)
+
+		strategy := ForMetric("9090/tcp", "/metrics").
+			WithName("queue_size").
+			WithMatcher(func(v float64) bool { return v < 10 }).
+			WithPollInterval(10 * time.Millisecond).
+			WithStartupTimeout(200 * time.Millisecond)
+
+		err := WaitUntilReady(context.Background(), strategy, addrTarget(t, server.Listener.Addr().String()))
+
+		var waitErr *WaitError
+		require.ErrorAs(t, err, &waitErr)
+		require.Len(t, waitErr.Attempts, 1)
+		require.Error(t, waitErr.Attempts[0].Err)
+
+		metric := waitErr.Attempts[0].Metric
+		require.NotNil(t, metric)
+		require.Equal(t, 2, metric.Found)
+		require.Len(t, metric.Samples, 2)
+		require.Contains(t, waitErr.Summary(), `2 samples: queue_size{queue="orders"} 12, queue_size{queue="payments"} 3, failed: metric queue_size{queue="orders"} 12 not matched`)
+	})
+
+	t.Run("exited", func(t *testing.T) {
+		target := addrTarget(t, "127.0.0.1:8080")
+		target.StateImpl = func(_ context.Context) (*container.State, error) {
+			return &container.State{Status: container.StateExited, ExitCode: 3}, nil
+		}
+
+		err := WaitUntilReady(context.Background(), ForListeningPort("8080/tcp").WithStartupTimeout(50*time.Millisecond), target)
+
+		var waitErr *WaitError
+		require.ErrorAs(t, err, &waitErr)
+		require.Empty(t, waitErr.Attempts)
+		require.Equal(t, err.Error()+"\ncontainer state: exited (exit code 3)", waitErr.Summary())
+	})
+
+	t.Run("state-error", func(t *testing.T) {
+		target := &MockStrategyTarget{
+			StateImpl: func(_ context.Context) (*container.State, error) {
+				return nil, errors.New("no such container")
+			},
+			LogsImpl: func(_ context.Context) (io.ReadCloser, error) {
+				return nil, errors.New("no such container")
+			},
+		}
+
+		err := WaitUntilReady(context.Background(), ForLog("ready").WithStartupTimeout(50*time.Millisecond), target)
+
+		var waitErr *WaitError
+		require.ErrorAs(t, err, &waitErr)
+		require.Nil(t, waitErr.State)
+		require.Equal(t, err.Error(), waitErr.Summary())
+	})
+}
+
+func TestExcerptReader(t *testing.T) {
+	t.Run("unread", func(t *testing.T) {
+		r := newExcerptReader(strings.NewReader("hello world"))
+		require.Equal(t, "hello world", r.String())
+	})
+
+	t.Run("partially-read", func(t *testing.T) {
+		r := newExcerptReader(strings.NewReader("hello world"))
+
+		b := make([]byte, 5)
+		_, err := io.ReadFull(r, b)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", r.String())
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		long := strings.Repeat("a", maxExcerptSize*2)
+		r := newExcerptReader(strings.NewReader(long))
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, long, string(b))
+		require.Equal(t, long[:maxExcerptSize], r.String())
+	})
+}
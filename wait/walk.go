@@ -68,6 +68,38 @@ func walk(root *Strategy, visit VisitFunc) error {
 		if err := walkAndMutate(&s.Strategies, visit); err != nil {
 			return err
 		}
+	case *SequenceStrategy:
+		if err := walkAndMutate(&s.Strategies, visit); err != nil {
+			return err
+		}
+	case *StableStrategy:
+		if err := walkAndRemove(&s.Strategy, visit); err != nil {
+			return err
+		}
+	case *RetryStrategy:
+		if err := walkAndRemove(&s.Strategy, visit); err != nil {
+			return err
+		}
+	case *NotStrategy:
+		if err := walkAndRemove(&s.Strategy, visit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walkAndRemove walks the single strategy wrapped by a strategy,
+// which is set to nil if it's removed.
+func walkAndRemove(strategy *Strategy, visit VisitFunc) error {
+	if err := walk(strategy, visit); err != nil {
+		if errors.Is(err, ErrVisitRemove) {
+			if errors.Is(err, ErrVisitStop) {
+				return ErrVisitStop
+			}
+			return nil
+		}
+		return err
 	}
 
 	return nil
@@ -3,6 +3,7 @@ package wait_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -103,6 +104,30 @@ func TestWalk(t *testing.T) {
 		requireVisits(t, req, 1)
 	})
 
+	t.Run("combinators", func(t *testing.T) {
+		req := testcontainers.ContainerRequest{
+			WaitingFor: wait.ForSequence(
+				wait.ForStable(wait.ForFile("/tmp/file"), 3, time.Second),
+				wait.ForRetry(wait.ForHTTP("/health"), 3),
+				wait.ForNot(wait.ForLog("panic")),
+			),
+		}
+		requireVisits(t, req, 7)
+
+		var matched int
+		err := wait.Walk(&req.WaitingFor, func(s wait.Strategy) error {
+			switch s.(type) {
+			case *wait.FileStrategy, *wait.LogStrategy:
+				matched++
+				return wait.ErrVisitRemove
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, matched)
+		requireVisits(t, req, 5)
+	})
+
 	t.Run("for-all-single", func(t *testing.T) {
 		req := testcontainers.ContainerRequest{
 			WaitingFor: wait.ForAll(